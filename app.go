@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/url"
 	"time"
@@ -11,6 +12,7 @@ import (
 	appsvc "refleks/internal/appsvc"
 	"refleks/internal/benchmarks"
 	"refleks/internal/constants"
+	"refleks/internal/events/wailssink"
 	"refleks/internal/models"
 	appsettings "refleks/internal/settings"
 	"refleks/internal/traces"
@@ -21,6 +23,8 @@ type App struct {
 	ctx      context.Context
 	appSvc   *appsvc.AppService
 	settings models.Settings
+	// settingsNotice explains a settings recovery at startup, if any.
+	settingsNotice string
 }
 
 // NewApp creates a new App application struct
@@ -31,11 +35,19 @@ func NewApp() *App { return &App{} }
 func (a *App) startup(ctx context.Context) {
 	a.ctx = ctx
 	runtime.LogInfo(a.ctx, "RefleK's app starting up")
-	// Load settings from disk
-	if s, err := appsettings.Load(); err == nil {
+	// Load settings from disk, recovering from a backup if the file is damaged
+	if s, from, err := appsettings.LoadRecovering(); err == nil {
 		a.settings = s
+		if from != "" {
+			a.settingsNotice = "Your settings file was damaged and has been restored from " + from + "."
+			runtime.LogWarning(a.ctx, "settings file damaged, recovered from "+from)
+			_ = appsettings.Save(a.settings)
+		}
 	} else {
 		runtime.LogWarning(a.ctx, "settings load failed, using defaults: "+err.Error())
+		if !errors.Is(err, appsettings.ErrNoSettings) {
+			a.settingsNotice = "Your settings could not be read and no backup was usable; defaults were loaded."
+		}
 		a.settings = appsettings.Default()
 		_ = appsettings.Save(a.settings)
 	}
@@ -47,7 +59,7 @@ func (a *App) startup(ctx context.Context) {
 	traces.SetBaseDir(tracesDir)
 
 	// Initialize coordinated AppService (wires mouse, watcher, updater)
-	a.appSvc = appsvc.NewAppService(a.ctx, &a.settings)
+	a.appSvc = appsvc.NewAppService(wailssink.New(a.ctx), &a.settings)
 
 	// Fire-and-forget check for app updates; emit event if available
 	go func() {
@@ -68,7 +80,7 @@ func (a *App) startup(ctx context.Context) {
 // StartWatcher begins monitoring the given directory for new Kovaak's CSV files.
 func (a *App) StartWatcher(path string) (bool, string) {
 	if a.appSvc == nil {
-		a.appSvc = appsvc.NewAppService(a.ctx, &a.settings)
+		a.appSvc = appsvc.NewAppService(wailssink.New(a.ctx), &a.settings)
 	}
 	return a.appSvc.StartWatcher(path)
 }
@@ -81,14 +93,52 @@ func (a *App) StopWatcher() (bool, string) {
 	return a.appSvc.StopWatcher()
 }
 
-// GetRecentScenarios returns most recent parsed scenarios, up to optional limit.
-func (a *App) GetRecentScenarios(limit int) []models.ScenarioRecord {
+// GetRecentScenarios returns summaries of the most recent parsed scenarios, up to
+// optional limit. Use GetScenarioDetail for kill rows and the mouse trace.
+func (a *App) GetRecentScenarios(limit int) []models.ScenarioSummary {
 	if a.appSvc == nil {
 		return nil
 	}
 	return a.appSvc.GetRecent(limit)
 }
 
+// GetScenarioDetail returns the full record of a scenario run, including its
+// kill events and persisted mouse trace, which are loaded on demand.
+func (a *App) GetScenarioDetail(filePath string) (models.ScenarioRecord, error) {
+	if a.appSvc == nil {
+		return models.ScenarioRecord{}, fmt.Errorf("watcher not started")
+	}
+	return a.appSvc.GetScenarioDetail(filePath)
+}
+
+// ImportStatsArchive imports runs from a .zip or .tar.gz backup of a Kovaak's stats folder.
+// source names the stats source (path or label) the runs belong to; empty
+// means the primary one. Progress is reported through "ImportProgress" events.
+func (a *App) ImportStatsArchive(path, source string) (models.ImportProgress, error) {
+	if a.appSvc == nil {
+		a.appSvc = appsvc.NewAppService(wailssink.New(a.ctx), &a.settings)
+	}
+	return a.appSvc.ImportStatsArchive(path, source)
+}
+
+// QueryScenarios filters, sorts and pages the scenario history. Unlike
+// GetRecentScenarios it returns one page at a time along with the total match count.
+func (a *App) QueryScenarios(q models.ScenarioQuery) (models.ScenarioPage, error) {
+	if a.appSvc == nil {
+		return models.ScenarioPage{Items: []models.ScenarioSummary{}}, nil
+	}
+	return a.appSvc.QueryScenarios(q)
+}
+
+// GetSessions returns the scenario sessions overlapping [from, to] (RFC3339,
+// empty for an open bound), most-recent-first. Sessions use the configured gap.
+func (a *App) GetSessions(from string, to string) ([]models.Session, error) {
+	if a.appSvc == nil {
+		return nil, nil
+	}
+	return a.appSvc.GetSessions(from, to)
+}
+
 // GetBenchmarks returns the embedded benchmarks list for the Explore UI.
 func (a *App) GetBenchmarks() ([]models.Benchmark, error) {
 	return benchmarks.GetBenchmarks()
@@ -105,8 +155,46 @@ func (a *App) GetBenchmarkProgress(benchmarkId int) (models.BenchmarkProgress, e
 	return data, nil
 }
 
+// CollectTraceGarbage applies the trace retention settings now and reports what was deleted.
+func (a *App) CollectTraceGarbage() (models.TraceGCReport, error) {
+	if a.appSvc == nil {
+		a.appSvc = appsvc.NewAppService(wailssink.New(a.ctx), &a.settings)
+	}
+	return a.appSvc.CollectTraceGarbage()
+}
+
+// ListTraces returns the stored mouse traces (metadata only), most recent first.
+func (a *App) ListTraces() ([]models.TraceInfo, error) {
+	return traces.List()
+}
+
+// DeleteTrace deletes the stored mouse trace of the given stats file.
+func (a *App) DeleteTrace(fileName string) error {
+	if a.appSvc == nil {
+		return traces.Delete(fileName)
+	}
+	return a.appSvc.DeleteTrace(fileName)
+}
+
+// MigrateTraces rewrites stored traces from older data versions (and legacy
+// JSON files) in the current format.
+func (a *App) MigrateTraces() (models.TraceMigrationReport, error) {
+	return traces.MigrateAll()
+}
+
+// GetTraceStats returns aggregate numbers for the trace store.
+func (a *App) GetTraceStats() (models.TraceStats, error) {
+	return traces.Stats()
+}
+
 // --- Settings IPC ---
 
+// GetSettingsRecovery returns a message when settings had to be restored from
+// a backup (or reset) at startup, and an empty string otherwise.
+func (a *App) GetSettingsRecovery() string {
+	return a.settingsNotice
+}
+
 // GetSettings returns the current settings.
 func (a *App) GetSettings() models.Settings {
 	return a.settings
@@ -115,7 +203,7 @@ func (a *App) GetSettings() models.Settings {
 // UpdateSettings updates settings and persists them; applies to watcher if needed.
 func (a *App) UpdateSettings(s models.Settings) (bool, string) {
 	if a.appSvc == nil {
-		a.appSvc = appsvc.NewAppService(a.ctx, &a.settings)
+		a.appSvc = appsvc.NewAppService(wailssink.New(a.ctx), &a.settings)
 	}
 	return a.appSvc.UpdateSettings(s)
 }
@@ -184,7 +272,7 @@ func (a *App) LaunchKovaaksPlaylist(sharecode string) (bool, string) {
 // CheckForUpdates queries GitHub releases and returns update availability and download URL.
 func (a *App) CheckForUpdates() (models.UpdateInfo, error) {
 	if a.appSvc == nil {
-		a.appSvc = appsvc.NewAppService(a.ctx, &a.settings)
+		a.appSvc = appsvc.NewAppService(wailssink.New(a.ctx), &a.settings)
 	}
 	return a.appSvc.CheckForUpdates(a.ctx)
 }
@@ -193,7 +281,7 @@ func (a *App) CheckForUpdates() (models.UpdateInfo, error) {
 // version may be empty to auto-detect latest.
 func (a *App) DownloadAndInstallUpdate(version string) (bool, string) {
 	if a.appSvc == nil {
-		a.appSvc = appsvc.NewAppService(a.ctx, &a.settings)
+		a.appSvc = appsvc.NewAppService(wailssink.New(a.ctx), &a.settings)
 	}
 	if err := a.appSvc.DownloadAndInstallUpdate(a.ctx, version); err != nil {
 		return false, err.Error()
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"refleks/internal/benchmarks"
+	"refleks/internal/models"
+)
+
+func runBenchmark(args []string) error {
+	if len(args) == 0 || args[0] != "progress" {
+		return errors.New("usage: refleks benchmark progress [-json] <id>")
+	}
+	fs := newFlags("benchmark progress", "benchmark progress [-json] <id>")
+	asJSON := fs.Bool("json", false, "print the progress as JSON")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("expected a benchmark id")
+	}
+	id, err := strconv.Atoi(fs.Arg(0))
+	if err != nil {
+		return fmt.Errorf("invalid benchmark id %q", fs.Arg(0))
+	}
+	loadSettings()
+	prog, err := benchmarks.GetBenchmarkProgress(id)
+	if err != nil {
+		return err
+	}
+	if *asJSON {
+		return writeJSON(os.Stdout, prog)
+	}
+	fmt.Printf("Overall rank: %s (progress %.0f)\n\n", rankName(prog.Ranks, prog.OverallRank), prog.BenchmarkProgress)
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "CATEGORY\tGROUP\tSCENARIO\tSCORE\tRANK\tNEXT")
+	for _, c := range prog.Categories {
+		for _, g := range c.Groups {
+			for _, sc := range g.Scenarios {
+				fmt.Fprintf(tw, "%s\t%s\t%s\t%.1f\t%s\t%s\n", c.Name, g.Name, sc.Name, sc.Score, rankName(prog.Ranks, sc.ScenarioRank), nextThreshold(sc))
+			}
+		}
+	}
+	return tw.Flush()
+}
+
+// rankName returns the name of a 1-based rank, or "-" when unranked.
+func rankName(ranks []models.RankDef, rank int) string {
+	if rank >= 1 && rank <= len(ranks) {
+		return ranks[rank-1].Name
+	}
+	if rank <= 0 {
+		return "-"
+	}
+	return strconv.Itoa(rank)
+}
+
+// nextThreshold returns the score needed for the next rank, if any.
+func nextThreshold(sc models.ScenarioProgress) string {
+	for _, t := range sc.Thresholds {
+		if t > sc.Score {
+			return strconv.FormatFloat(t, 'f', -1, 64)
+		}
+	}
+	return "-"
+}
@@ -0,0 +1,160 @@
+// Command refleks is the headless command-line interface of RefleK's. It
+// reuses the parser, watcher, benchmarks and sens packages of the desktop app
+// so reports and analyses can be scripted without the GUI.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"refleks/internal/events"
+	"refleks/internal/models"
+	"refleks/internal/settings"
+	"refleks/internal/traces"
+	"refleks/internal/watcher"
+)
+
+// command is one CLI subcommand.
+type command struct {
+	name    string
+	usage   string
+	summary string
+	run     func(args []string) error
+}
+
+var commands = []command{
+	{"parse", "parse [-json] <stats.csv>", "parse one stats file and print its stats", runParse},
+	{"scan", "scan [-json] [-limit n] [dir]", "print a summary table of the runs in a stats directory", runScan},
+	{"sessions", "sessions [-json] [-dir d] [-from t] [-to t]", "group runs into sessions", runSessions},
+	{"export", "export [-format csv|json] [-o file] [-dir d] [filters]", "export runs matching a query", runExport},
+	{"benchmark", "benchmark progress [-json] <id>", "show benchmark progress from Kovaak's", runBenchmark},
+	{"sens", "sens convert -from scale -to scale -sens v [-dpi n]", "convert a sensitivity between scales", runSens},
+	{"watch", "watch [-dir d] [-jsonl file] [-v]", "watch stats directories and print events", runWatch},
+}
+
+func main() {
+	if len(os.Args) < 2 || os.Args[1] == "-h" || os.Args[1] == "help" || os.Args[1] == "--help" {
+		usage(os.Stdout)
+		return
+	}
+	for _, c := range commands {
+		if c.name == os.Args[1] {
+			if err := c.run(os.Args[2:]); err != nil {
+				if !errors.Is(err, flag.ErrHelp) {
+					fmt.Fprintf(os.Stderr, "refleks %s: %v\n", c.name, err)
+				}
+				os.Exit(1)
+			}
+			return
+		}
+	}
+	fmt.Fprintf(os.Stderr, "refleks: unknown command %q\n\n", os.Args[1])
+	usage(os.Stderr)
+	os.Exit(2)
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: refleks <command> [flags]")
+	fmt.Fprintln(w)
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for _, c := range commands {
+		fmt.Fprintf(tw, "  %s\t%s\n", c.usage, c.summary)
+	}
+	tw.Flush()
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Stats directories, time zones and the session gap come from the app settings")
+	fmt.Fprintln(w, "unless overridden with -dir. Run 'refleks <command> -h' for the flags of a command.")
+}
+
+// newFlags returns a flag set for a subcommand that reports errors instead of exiting.
+func newFlags(name, usage string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: refleks %s\n", usage)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// loadSettings returns the saved app settings, or the defaults when there are
+// none, and points the traces store at the configured directory.
+func loadSettings() models.Settings {
+	s, err := settings.Load()
+	if err != nil {
+		s = settings.Default()
+	}
+	s = settings.Sanitize(s)
+	traces.SetBaseDir(settings.ExpandPathPlaceholders(s.TracesDir))
+	return s
+}
+
+// quietSink drops events and writes warnings and errors to stderr, so command
+// output stays machine-readable.
+type quietSink struct{ *events.Stdout }
+
+func (quietSink) Emit(string, any) {}
+
+func newQuietSink() events.EventSink {
+	return quietSink{events.NewStdout(events.Warning)}
+}
+
+// watcherConfig returns the watcher configuration for dir, or for the stats
+// directories in the settings when dir is empty. With all set every existing
+// file is loaded and kept, otherwise only the newest MaxExistingOnStart.
+func watcherConfig(s models.Settings, dir string, all bool) models.WatcherConfig {
+	var cfg models.WatcherConfig
+	if dir == "" {
+		cfg = settings.WatcherConfig(s.StatsDir, s)
+	} else {
+		cfg = settings.WatcherConfig(dir, models.Settings{SessionGapMinutes: s.SessionGapMinutes, StatsTimezone: s.StatsTimezone})
+	}
+	if all {
+		cfg.ParseExistingLimit = 0
+		cfg.RecentCap = -1
+	}
+	// The record index is shared with the desktop app and scanning another
+	// directory would evict its entries, so the CLI always parses.
+	cfg.IndexPath = ""
+	return cfg
+}
+
+// loadDir scans dir (or the configured directories) once and returns the
+// watcher holding the parsed runs.
+func loadDir(dir string) (*watcher.Watcher, error) {
+	s := loadSettings()
+	cfg := watcherConfig(s, dir, true)
+	if cfg.Path == "" {
+		return nil, errors.New("no stats directory configured; pass -dir")
+	}
+	w := watcher.New(newQuietSink(), cfg)
+	if err := w.Scan(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// writeJSON prints v as indented JSON.
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// pct formats a 0..1 ratio as a percentage.
+func pct(v float64) string {
+	return fmt.Sprintf("%.1f%%", v*100)
+}
+
+// shortTime formats an RFC3339 timestamp for tables, dropping the offset.
+func shortTime(s string) string {
+	if len(s) < 19 {
+		return s
+	}
+	return strings.Replace(s[:19], "T", " ", 1)
+}
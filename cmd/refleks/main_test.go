@@ -0,0 +1,81 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"refleks/internal/constants"
+	"refleks/internal/models"
+)
+
+// writeSyntheticRuns writes n stats files a minute apart, each with its own
+// score so they are not deduplicated as copies of one run.
+func writeSyntheticRuns(t *testing.T, dir string, n int) {
+	t.Helper()
+	tmpl, err := os.ReadFile(filepath.Join("..", "..", "testdata", "stats", "VT 1w3ts Intermediate S5 - Challenge - 2025.10.02-18.36.37 Stats.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	score := regexp.MustCompile(`(?m)^Score:,.*$`)
+	start := time.Date(2025, 1, 1, 8, 0, 0, 0, time.Local)
+	for i := 0; i < n; i++ {
+		played := start.Add(time.Duration(i) * time.Minute)
+		name := fmt.Sprintf("VT 1w3ts Intermediate S5 - Challenge - %s Stats.csv", played.Format("2006.01.02-15.04.05"))
+		body := score.ReplaceAll(tmpl, []byte(fmt.Sprintf("Score:,%d.0", 1000+i)))
+		if err := os.WriteFile(filepath.Join(dir, name), body, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestLoadDirKeepsEveryRun(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("USERPROFILE", os.Getenv("HOME"))
+	dir := t.TempDir()
+	n := constants.DefaultRecentCap + 100
+	writeSyntheticRuns(t, dir, n)
+
+	w, err := loadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := len(w.GetRecent(0)); got != n {
+		t.Errorf("scan: %d runs, want %d", got, n)
+	}
+	page, err := w.QueryScenarios(models.ScenarioQuery{Limit: constants.MaxQueryLimit}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if page.Total != n {
+		t.Errorf("export query: %d runs, want %d", page.Total, n)
+	}
+	var inSessions int
+	for _, s := range w.GetSessions(time.Time{}, time.Time{}) {
+		inSessions += len(s.Items)
+	}
+	if inSessions != n {
+		t.Errorf("sessions: %d runs, want %d", inSessions, n)
+	}
+
+	out := filepath.Join(t.TempDir(), "runs.csv")
+	if err := runExport([]string{"-dir", dir, "-o", out}); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	rows, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != n+1 {
+		t.Errorf("export wrote %d rows, want %d plus the header", len(rows)-1, n)
+	}
+}
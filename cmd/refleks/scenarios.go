@@ -0,0 +1,244 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"refleks/internal/constants"
+	"refleks/internal/models"
+	"refleks/internal/watcher"
+)
+
+func runParse(args []string) error {
+	fs := newFlags("parse", "parse [-json] <stats.csv>")
+	asJSON := fs.Bool("json", false, "print the full record as JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("expected one stats file")
+	}
+	path, err := filepath.Abs(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	s := loadSettings()
+	w := watcher.New(newQuietSink(), watcherConfig(s, filepath.Dir(path), true))
+	rec, err := w.GetDetail(path)
+	if err != nil {
+		return err
+	}
+	if *asJSON {
+		return writeJSON(os.Stdout, rec)
+	}
+	st := rec.ScenarioStats
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "Scenario\t%v\n", rec.Stats["Scenario"])
+	fmt.Fprintf(tw, "Mode\t%s\n", rec.Mode)
+	fmt.Fprintf(tw, "Played\t%v\n", rec.Stats["Date Played"])
+	fmt.Fprintf(tw, "Score\t%.1f\n", st.Score)
+	fmt.Fprintf(tw, "Accuracy\t%s (%d hits, %d misses)\n", pct(st.Accuracy), st.HitCount, st.MissCount)
+	fmt.Fprintf(tw, "Kills\t%d\n", len(rec.Kills))
+	fmt.Fprintf(tw, "Real avg TTK\t%.3fs\n", st.RealAvgTTK)
+	fmt.Fprintf(tw, "Sensitivity\t%g %s (%.1f cm/360)\n", st.HorizSens, st.SensScale, st.Cm360)
+	fmt.Fprintf(tw, "Trace\t%v\n", rec.HasTrace)
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	if len(rec.Weapons) > 0 {
+		fmt.Println()
+		tw = tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "WEAPON\tSHOTS\tHITS\tACC\tEFFICIENCY")
+		for _, ws := range rec.Weapons {
+			fmt.Fprintf(tw, "%s\t%d\t%d\t%s\t%s\n", ws.Weapon, ws.Shots, ws.Hits, pct(ws.Accuracy), pct(ws.Efficiency))
+		}
+		return tw.Flush()
+	}
+	return nil
+}
+
+func runScan(args []string) error {
+	fs := newFlags("scan", "scan [-json] [-limit n] [dir]")
+	asJSON := fs.Bool("json", false, "print run summaries as JSON")
+	limit := fs.Int("limit", 0, "only show the n most recent runs (0 = all)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	w, err := loadDir(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	runs := w.GetRecent(*limit)
+	if *asJSON {
+		if runs == nil {
+			runs = []models.ScenarioSummary{}
+		}
+		return writeJSON(os.Stdout, runs)
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "PLAYED\tSCENARIO\tMODE\tSCORE\tACC\tCM/360\tSOURCE")
+	for _, r := range runs {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%.1f\t%s\t%.1f\t%s\n", shortTime(r.DatePlayed), r.ScenarioName, r.Mode,
+			r.ScenarioStats.Score, pct(r.ScenarioStats.Accuracy), r.ScenarioStats.Cm360, r.Source)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "%d runs\n", len(runs))
+	return nil
+}
+
+func runSessions(args []string) error {
+	fs := newFlags("sessions", "sessions [-json] [-dir d] [-from t] [-to t]")
+	asJSON := fs.Bool("json", false, "print sessions with their runs as JSON")
+	dir := fs.String("dir", "", "stats directory (default: from settings)")
+	from := fs.String("from", "", "only sessions ending after this time (RFC3339 or YYYY-MM-DD)")
+	to := fs.String("to", "", "only sessions starting before this time (RFC3339 or YYYY-MM-DD)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	start, err := parseTimeFlag(*from, false)
+	if err != nil {
+		return fmt.Errorf("invalid -from: %w", err)
+	}
+	end, err := parseTimeFlag(*to, true)
+	if err != nil {
+		return fmt.Errorf("invalid -to: %w", err)
+	}
+	w, err := loadDir(*dir)
+	if err != nil {
+		return err
+	}
+	list := w.GetSessions(start, end)
+	if *asJSON {
+		if list == nil {
+			list = []models.Session{}
+		}
+		return writeJSON(os.Stdout, list)
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tSTART\tEND\tDURATION\tRUNS\tSCENARIOS")
+	for _, s := range list {
+		names := make(map[string]struct{})
+		for _, it := range s.Items {
+			names[it.ScenarioName] = struct{}{}
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%d\t%d\n", s.ID, shortTime(s.Start), shortTime(s.End), sessionDuration(s), len(s.Items), len(names))
+	}
+	return tw.Flush()
+}
+
+func sessionDuration(s models.Session) string {
+	a, err1 := time.Parse(time.RFC3339, s.Start)
+	b, err2 := time.Parse(time.RFC3339, s.End)
+	if err1 != nil || err2 != nil {
+		return "?"
+	}
+	return b.Sub(a).Round(time.Second).String()
+}
+
+// parseTimeFlag accepts RFC3339 or a local date. A date used as an upper
+// bound covers the whole day.
+func parseTimeFlag(v string, endOfDay bool) (time.Time, error) {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, nil
+	}
+	d, err := time.ParseInLocation("2006-01-02", v, time.Local)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if endOfDay {
+		d = d.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	}
+	return d, nil
+}
+
+func runExport(args []string) error {
+	fs := newFlags("export", "export [-format csv|json] [-o file] [-dir d] [filters]")
+	format := fs.String("format", "csv", "output format: csv or json")
+	out := fs.String("o", "", "output file (default: stdout)")
+	dir := fs.String("dir", "", "stats directory (default: from settings)")
+	name := fs.String("name", "", "scenario name substring or glob")
+	from := fs.String("from", "", "runs played at or after this time (RFC3339 or YYYY-MM-DD)")
+	to := fs.String("to", "", "runs played at or before this time (RFC3339 or YYYY-MM-DD)")
+	mode := fs.String("mode", "", "play mode, e.g. Challenge")
+	sortBy := fs.String("sort", "date", "sort key: date, score, accuracy, cm360 or name")
+	asc := fs.Bool("asc", false, "sort ascending")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *format != "csv" && *format != "json" {
+		return fmt.Errorf("unknown format %q", *format)
+	}
+	q := models.ScenarioQuery{Name: *name, Mode: *mode, SortBy: *sortBy, Ascending: *asc}
+	for _, b := range []struct {
+		v      string
+		dst    *string
+		endDay bool
+	}{{*from, &q.From, false}, {*to, &q.To, true}} {
+		t, err := parseTimeFlag(b.v, b.endDay)
+		if err != nil {
+			return err
+		}
+		if !t.IsZero() {
+			*b.dst = t.Format(time.RFC3339)
+		}
+	}
+	w, err := loadDir(*dir)
+	if err != nil {
+		return err
+	}
+	tags := loadSettings().ScenarioTags
+	runs := []models.ScenarioSummary{}
+	for {
+		q.Offset, q.Limit = len(runs), constants.MaxQueryLimit
+		page, err := w.QueryScenarios(q, tags)
+		if err != nil {
+			return err
+		}
+		runs = append(runs, page.Items...)
+		if len(page.Items) == 0 || len(runs) >= page.Total {
+			break
+		}
+	}
+
+	var dst io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		dst = f
+	}
+	if *format == "json" {
+		return writeJSON(dst, runs)
+	}
+	return writeCSV(dst, runs)
+}
+
+func writeCSV(dst io.Writer, runs []models.ScenarioSummary) error {
+	cw := csv.NewWriter(dst)
+	_ = cw.Write([]string{"played_at", "date_played", "scenario", "mode", "score", "accuracy", "hits", "misses", "real_avg_ttk", "cm360", "source", "file_path"})
+	f := func(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) }
+	for _, r := range runs {
+		st := r.ScenarioStats
+		_ = cw.Write([]string{r.PlayedAt, r.DatePlayed, r.ScenarioName, r.Mode, f(st.Score), f(st.Accuracy),
+			strconv.Itoa(st.HitCount), strconv.Itoa(st.MissCount), f(st.RealAvgTTK), f(st.Cm360), r.Source, r.FilePath})
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"refleks/internal/sens"
+)
+
+func runSens(args []string) error {
+	if len(args) == 0 || args[0] != "convert" {
+		return errors.New("usage: refleks sens convert -from scale -to scale -sens v [-dpi n]")
+	}
+	fs := newFlags("sens convert", "sens convert -from scale -to scale -sens v [-dpi n]")
+	scales := strings.Join(sens.Scales(), ", ")
+	from := fs.String("from", "", "source scale ("+scales+")")
+	to := fs.String("to", "cm/360", "target scale ("+scales+")")
+	value := fs.Float64("sens", 0, "sensitivity on the source scale")
+	dpi := fs.Float64("dpi", 0, "mouse DPI (required for game scales)")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	if *from == "" || *value <= 0 {
+		fs.Usage()
+		return errors.New("-from and a positive -sens are required")
+	}
+	cm, ok := sens.Cm360(*from, *value, *dpi)
+	if !ok {
+		return fmt.Errorf("cannot convert from %q (supported: %s; game scales need -dpi)", *from, scales)
+	}
+	out, ok := sens.FromCm360(*to, cm, *dpi)
+	if !ok {
+		return fmt.Errorf("cannot convert to %q (supported: %s; game scales need -dpi)", *to, scales)
+	}
+	fmt.Printf("%.4g %s (%.2f cm/360)\n", out, *to, cm)
+	return nil
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"refleks/internal/events"
+	"refleks/internal/watcher"
+)
+
+func runWatch(args []string) error {
+	fs := newFlags("watch", "watch [-dir d] [-jsonl file] [-v]")
+	dir := fs.String("dir", "", "stats directory (default: from settings)")
+	jsonl := fs.String("jsonl", "", "also append events and logs to this JSON-lines file")
+	verbose := fs.Bool("v", false, "print debug logs")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	level := events.Info
+	if *verbose {
+		level = events.Debug
+	}
+	sink := events.EventSink(events.NewStdout(level))
+	if *jsonl != "" {
+		f, err := events.OpenJSONLinesFile(*jsonl)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		f.MinLevel = level
+		sink = events.Multi(sink, f)
+	}
+
+	s := loadSettings()
+	cfg := watcherConfig(s, *dir, false)
+	if cfg.Path == "" {
+		return errors.New("no stats directory configured; pass -dir")
+	}
+	w := watcher.New(sink, cfg)
+	if err := w.Start(); err != nil {
+		return err
+	}
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+	<-sig
+	return w.Stop()
+}
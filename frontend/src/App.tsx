@@ -1,11 +1,11 @@
 import { HelpCircle, Settings as SettingsIcon } from 'lucide-react'
 import type { ReactNode } from 'react'
-import { useEffect, useRef, useState } from 'react'
+import { useCallback, useEffect, useRef, useState } from 'react'
 import { NavLink, Outlet, Route, Routes } from 'react-router-dom'
 import { BrowserOpenURL, EventsOn } from '../wailsjs/runtime'
 import { DISCORD_SYMBOL, KO_FI_SYMBOL } from './assets'
 import { StoreProvider, useStore } from './hooks/useStore'
-import { checkForUpdates, downloadAndInstallUpdate, getRecentScenarios, getSettings, getVersion, startWatcher } from './lib/internal'
+import { checkForUpdates, downloadAndInstallUpdate, getRecentScenarios, getSessions, getSettingsRecovery, getVersion, startWatcher } from './lib/internal'
 import { applyTheme, getSavedTheme } from './lib/theme'
 import { BenchmarksPage } from './pages/Benchmarks'
 import { ScenariosPage } from './pages/Scenarios'
@@ -28,8 +28,10 @@ function Link({ to, children, end = false }: { to: string, children: ReactNode,
 function TopNav() {
   const [version, setVersion] = useState<string>('')
   const [update, setUpdate] = useState<UpdateInfo | null>(null)
+  const [settingsNotice, setSettingsNotice] = useState('')
   useEffect(() => {
     getVersion().then(v => setVersion(v)).catch(() => setVersion(''))
+    getSettingsRecovery().then(setSettingsNotice).catch(() => setSettingsNotice(''))
     // Proactive check (also handled by backend event)
     checkForUpdates().then((info) => { if (info?.hasUpdate) setUpdate(info) }).catch(() => { })
     // Listen for backend event
@@ -46,6 +48,15 @@ function TopNav() {
       <div className="flex items-center gap-2">
         <div className="font-semibold">RefleK's</div>
         {version && <span className="text-[10px] px-2 py-0.5 rounded-full border border-[var(--border-primary)] text-[var(--text-secondary)]">v{version}</span>}
+        {settingsNotice && (
+          <button
+            className="text-[10px] px-2 py-0.5 rounded-full border border-yellow-500 text-yellow-500"
+            title={settingsNotice}
+            onClick={() => setSettingsNotice('')}
+          >
+            Settings restored
+          </button>
+        )}
         {update?.hasUpdate && (
           <div className="flex items-center gap-2">
             <button
@@ -127,11 +138,24 @@ function TopNav() {
 function AppLayout() {
   const addScenario = useStore(s => s.addScenario)
   const updateScenario = useStore(s => s.updateScenario)
+  const removeScenario = useStore(s => s.removeScenario)
   const incNew = useStore(s => s.incNew)
   const resetNew = useStore(s => s.resetNew)
   const setScenarios = useStore(s => s.setScenarios)
-  const setSessionGap = useStore(s => s.setSessionGap)
+  const setSessions = useStore(s => s.setSessions)
   const startedRef = useRef(false)
+  const sessionsTimerRef = useRef<number | null>(null)
+
+  // Sessions are grouped by the backend; refetch shortly after runs change so bursts coalesce
+  const refreshSessions = useCallback(() => {
+    if (sessionsTimerRef.current) window.clearTimeout(sessionsTimerRef.current)
+    sessionsTimerRef.current = window.setTimeout(() => {
+      sessionsTimerRef.current = null
+      getSessions()
+        .then((arr) => { setSessions(arr) })
+        .catch((err: unknown) => console.warn('GetSessions failed:', err))
+    }, 200)
+  }, [setSessions])
 
   // Startup effect: run once to start watcher and load initial data
   useEffect(() => {
@@ -145,11 +169,8 @@ function AppLayout() {
       .then((arr) => { setScenarios(arr) })
       .catch((err: unknown) => console.warn('GetRecentScenarios failed:', err))
 
-    // Initialize session gap for session grouping
-    getSettings()
-      .then((s) => { if (s && typeof s.sessionGapMinutes === 'number') setSessionGap(s.sessionGapMinutes) })
-      .catch(() => { })
-  }, [setScenarios, setSessionGap])
+    refreshSessions()
+  }, [setScenarios, refreshSessions])
 
   // Subscriptions effect: keep separate so it can cleanup/re-subscribe if handlers change
   useEffect(() => {
@@ -158,6 +179,7 @@ function AppLayout() {
       if (rec) {
         addScenario(rec)
         incNew()
+        refreshSessions()
       }
     })
 
@@ -165,21 +187,56 @@ function AppLayout() {
       const rec = data && data.filePath && data.stats ? data : null
       if (rec) {
         updateScenario(rec)
+        refreshSessions()
+      }
+    })
+
+    const offRm = EventsOn('ScenarioRemoved', (data: any) => {
+      // Stats file deleted or renamed away; a rename is followed by ScenarioUpdated
+      if (data && data.filePath) {
+        removeScenario(data.filePath)
+        refreshSessions()
+      }
+    })
+
+    const offLoaded = EventsOn('ScenariosLoaded', (data: any) => {
+      // Initial scan delivers all existing runs in one batch (most-recent-first)
+      if (Array.isArray(data)) setScenarios(data)
+      refreshSessions()
+    })
+
+    // A session opened or closed (e.g. after the idle gap); labels and bounds may have changed
+    const offSessStart = EventsOn('SessionStarted', () => refreshSessions())
+    const offSessEnd = EventsOn('SessionEnded', () => refreshSessions())
+
+    const offImport = EventsOn('ImportProgress', (data: any) => {
+      // Imported runs are merged into the backend list out of order; reload once done
+      if (data && data.done && data.imported > 0) {
+        getRecentScenarios(0)
+          .then((arr) => { setScenarios(arr) })
+          .catch((err: unknown) => console.warn('GetRecentScenarios failed:', err))
+        refreshSessions()
       }
     })
 
     const offWatcher = EventsOn('WatcherStarted', (_data: any) => {
       // Clear current scenarios so re-parsed existing files don't duplicate entries
       setScenarios([])
+      setSessions([])
       resetNew()
     })
 
     return () => {
       try { off() } catch (e) { /* ignore */ }
       try { offUpd() } catch (e) { /* ignore */ }
+      try { offRm() } catch (e) { /* ignore */ }
+      try { offLoaded() } catch (e) { /* ignore */ }
+      try { offSessStart() } catch (e) { /* ignore */ }
+      try { offSessEnd() } catch (e) { /* ignore */ }
+      try { offImport() } catch (e) { /* ignore */ }
       try { offWatcher() } catch (e) { /* ignore */ }
     }
-  }, [addScenario, updateScenario, incNew, setScenarios, resetNew])
+  }, [addScenario, updateScenario, removeScenario, incNew, setScenarios, setSessions, resetNew, refreshSessions])
 
   return (
     <div className="flex flex-col h-screen bg-[var(--bg-primary)] text-[var(--text-primary)]">
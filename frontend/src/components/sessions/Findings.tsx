@@ -2,17 +2,17 @@ import { ChevronRight } from 'lucide-react'
 import { useNavigate } from 'react-router-dom'
 import { usePageState } from '../../hooks/usePageState'
 import { formatNumber, formatPct01, formatSeconds, getScenarioName } from '../../lib/utils'
-import type { ScenarioRecord } from '../../types/ipc'
+import type { ScenarioSummary } from '../../types/ipc'
 import { Dropdown } from '../shared/Dropdown'
 
-type FindingsProps = { strongest: ScenarioRecord[]; weakest: ScenarioRecord[] }
-type FindingsRowProps = { rec: ScenarioRecord }
+type FindingsProps = { strongest: ScenarioSummary[]; weakest: ScenarioSummary[] }
+type FindingsRowProps = { rec: ScenarioSummary }
 
 export function Findings({ strongest, weakest }: FindingsProps) {
   const [openTab, setOpenTab] = usePageState<'analysis' | 'raw'>('findings:openIn', 'analysis')
   const navigate = useNavigate()
 
-  const openItem = (rec: ScenarioRecord) => {
+  const openItem = (rec: ScenarioSummary) => {
     const file = encodeURIComponent(rec.filePath)
     const tab = openTab
     navigate(`/scenarios?file=${file}&tab=${tab}`)
@@ -4,10 +4,10 @@ import { ChartBox } from '..'
 import { useChartTheme } from '../../hooks/useChartTheme'
 import { usePageState } from '../../hooks/usePageState'
 import { CHART_DECIMALS, formatNumber, formatPct, formatUiValueForLabel, getScenarioName } from '../../lib/utils'
-import type { ScenarioRecord } from '../../types/ipc'
+import type { ScenarioSummary } from '../../types/ipc'
 
 type PerformanceVsSensChartProps = {
-  items: ScenarioRecord[]
+  items: ScenarioSummary[]
   scenarioName: string
 }
 
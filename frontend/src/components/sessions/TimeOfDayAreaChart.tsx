@@ -2,9 +2,9 @@ import { useMemo } from 'react'
 import { Line } from 'react-chartjs-2'
 import { useChartTheme } from '../../hooks/useChartTheme'
 import { CHART_DECIMALS, formatNumber } from '../../lib/utils'
-import type { ScenarioRecord } from '../../types/ipc'
+import type { ScenarioSummary } from '../../types/ipc'
 
-type TimeOfDayAreaChartProps = { items: ScenarioRecord[] }
+type TimeOfDayAreaChartProps = { items: ScenarioSummary[] }
 
 export function TimeOfDayAreaChart({ items }: TimeOfDayAreaChartProps) {
   const theme = useChartTheme()
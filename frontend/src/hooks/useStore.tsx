@@ -1,60 +1,66 @@
 import type { ReactNode } from 'react'
 import { createContext, useCallback, useContext, useMemo, useReducer } from 'react'
 import type { Session } from '../types/domain'
-import type { ScenarioRecord } from '../types/ipc'
+import type { ScenarioSummary } from '../types/ipc'
 
 type State = {
-  scenarios: ScenarioRecord[]
+  scenarios: ScenarioSummary[]
   newScenarios: number
+  // Grouped by the backend (GetSessions), most-recent-first
   sessions: Session[]
-  sessionGapMinutes: number
 }
 
 type Action =
-  | { type: 'set'; items: ScenarioRecord[] }
-  | { type: 'add'; item: ScenarioRecord }
-  | { type: 'update'; item: ScenarioRecord }
+  | { type: 'set'; items: ScenarioSummary[] }
+  | { type: 'add'; item: ScenarioSummary }
+  | { type: 'update'; item: ScenarioSummary }
+  | { type: 'remove'; filePath: string }
   | { type: 'incNew' }
   | { type: 'resetNew' }
-  | { type: 'setGap'; minutes: number }
+  | { type: 'setSessions'; sessions: Session[] }
 
-const initial: State = { scenarios: [], newScenarios: 0, sessions: [], sessionGapMinutes: 30 }
+const initial: State = { scenarios: [], newScenarios: 0, sessions: [] }
 
 function reducer(state: State, action: Action): State {
   switch (action.type) {
     case 'set':
-      return { ...state, scenarios: action.items ?? [], sessions: groupSessions(action.items ?? [], state.sessionGapMinutes) }
+      return { ...state, scenarios: action.items ?? [] }
     case 'add':
-      return { ...state, scenarios: [action.item, ...state.scenarios], sessions: groupSessions([action.item, ...state.scenarios], state.sessionGapMinutes) }
+      return { ...state, scenarios: [action.item, ...state.scenarios] }
     case 'update': {
       const idx = state.scenarios.findIndex(s => s.filePath === action.item.filePath)
       if (idx === -1) {
         // if unknown, append without incrementing newScenarios
-        const next = [action.item, ...state.scenarios]
-        return { ...state, scenarios: next, sessions: groupSessions(next, state.sessionGapMinutes) }
+        return { ...state, scenarios: [action.item, ...state.scenarios] }
       }
       const next = [...state.scenarios]
       next[idx] = action.item
-      return { ...state, scenarios: next, sessions: groupSessions(next, state.sessionGapMinutes) }
+      return { ...state, scenarios: next }
+    }
+    case 'remove': {
+      const next = state.scenarios.filter(s => s.filePath !== action.filePath)
+      if (next.length === state.scenarios.length) return state
+      return { ...state, scenarios: next }
     }
     case 'incNew':
       return { ...state, newScenarios: state.newScenarios + 1 }
     case 'resetNew':
       return { ...state, newScenarios: 0 }
-    case 'setGap':
-      return { ...state, sessionGapMinutes: Math.max(1, Math.floor(action.minutes)), sessions: groupSessions(state.scenarios, Math.max(1, Math.floor(action.minutes))) }
+    case 'setSessions':
+      return { ...state, sessions: action.sessions ?? [] }
     default:
       return state
   }
 }
 
 type Ctx = State & {
-  setScenarios: (items: ScenarioRecord[]) => void
-  addScenario: (item: ScenarioRecord) => void
-  updateScenario: (item: ScenarioRecord) => void
+  setScenarios: (items: ScenarioSummary[]) => void
+  addScenario: (item: ScenarioSummary) => void
+  updateScenario: (item: ScenarioSummary) => void
+  removeScenario: (filePath: string) => void
   incNew: () => void
   resetNew: () => void
-  setSessionGap: (minutes: number) => void
+  setSessions: (sessions: Session[]) => void
 }
 
 const StoreCtx = createContext<Ctx | null>(null)
@@ -63,22 +69,24 @@ export function StoreProvider({ children }: { children: ReactNode }) {
   const [state, dispatch] = useReducer(reducer, initial)
 
   // Stable callbacks so consumers can safely depend on their identity
-  const setScenarios = useCallback((items: ScenarioRecord[]) => dispatch({ type: 'set', items }), [dispatch])
-  const addScenario = useCallback((item: ScenarioRecord) => dispatch({ type: 'add', item }), [dispatch])
-  const updateScenario = useCallback((item: ScenarioRecord) => dispatch({ type: 'update', item }), [dispatch])
+  const setScenarios = useCallback((items: ScenarioSummary[]) => dispatch({ type: 'set', items }), [dispatch])
+  const addScenario = useCallback((item: ScenarioSummary) => dispatch({ type: 'add', item }), [dispatch])
+  const updateScenario = useCallback((item: ScenarioSummary) => dispatch({ type: 'update', item }), [dispatch])
+  const removeScenario = useCallback((filePath: string) => dispatch({ type: 'remove', filePath }), [dispatch])
   const incNew = useCallback(() => dispatch({ type: 'incNew' }), [dispatch])
   const resetNew = useCallback(() => dispatch({ type: 'resetNew' }), [dispatch])
-  const setSessionGap = useCallback((minutes: number) => dispatch({ type: 'setGap', minutes }), [dispatch])
+  const setSessions = useCallback((sessions: Session[]) => dispatch({ type: 'setSessions', sessions }), [dispatch])
 
   const value = useMemo<Ctx>(() => ({
     ...state,
     setScenarios,
     addScenario,
     updateScenario,
+    removeScenario,
     incNew,
     resetNew,
-    setSessionGap,
-  }), [state, setScenarios, addScenario, updateScenario, incNew, resetNew, setSessionGap])
+    setSessions,
+  }), [state, setScenarios, addScenario, updateScenario, removeScenario, incNew, resetNew, setSessions])
   return <StoreCtx.Provider value={value}>{children}</StoreCtx.Provider>
 }
 
@@ -87,57 +95,3 @@ export function useStore<T>(selector: (s: Ctx) => T): T {
   if (!ctx) throw new Error('StoreProvider missing')
   return selector(ctx)
 }
-
-// --- Helpers ---
-function groupSessions(items: ScenarioRecord[], gapMinutes = 30): Session[] {
-  if (!Array.isArray(items) || items.length === 0) return []
-  // Ensure newest first
-  const sorted = [...items].sort((a, b) => endTs(b) - endTs(a))
-  const sessions: Session[] = []
-  let current: Session | null = null
-  for (const it of sorted) {
-    const t = endTs(it)
-    if (!current) {
-      current = { id: `sess-${t}`, start: startIso(it), end: endIso(it), items: [it] }
-      sessions.push(current)
-      continue
-    }
-    const last = current.items[current.items.length - 1]
-    const dt = Math.abs(endTs(last) - t)
-    if (dt <= gapMinutes * 60 * 1000) {
-      current.items.push(it)
-      // Maintain session bounds: start = earliest scenario start, end = latest scenario end
-      const curStartMs = Date.parse(current.start)
-      const curEndMs = Date.parse(current.end)
-      const itStartMs = startTs(it)
-      const itEndMs = endTs(it)
-      current.start = curStartMs <= itStartMs ? current.start : startIso(it)
-      current.end = curEndMs >= itEndMs ? current.end : endIso(it)
-    } else {
-      current = { id: `sess-${t}`, start: startIso(it), end: endIso(it), items: [it] }
-      sessions.push(current)
-    }
-  }
-  return sessions
-}
-
-// --- Timestamp helpers (simplified: fixed keys, no fallbacks) ---
-function endIso(s: ScenarioRecord): string {
-  return s.stats['Date Played'] as string
-}
-function endTs(s: ScenarioRecord): number {
-  return Date.parse(endIso(s))
-}
-function startIso(s: ScenarioRecord): string {
-  const end = endIso(s)
-  const datePart = end.split('T')[0]
-  const time = s.stats['Challenge Start'] as string
-  // Reuse timezone suffix from endIso (e.g. "+07:00" or "Z") so the constructed
-  // start timestamp uses the same timezone context instead of forcing UTC.
-  const tzMatch = String(end).match(/([+-]\d{2}:\d{2}|Z)$/)
-  const tz = tzMatch ? tzMatch[0] : 'Z'
-  return `${datePart}T${time}${tz}`
-}
-function startTs(s: ScenarioRecord): number {
-  return Date.parse(startIso(s))
-}
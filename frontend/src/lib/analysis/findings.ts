@@ -1,9 +1,9 @@
-import type { ScenarioRecord } from '../../types/ipc';
+import type { ScenarioSummary } from '../../types/ipc';
 
-// Compute 'findings' (strongest/weakest runs) from a list of ScenarioRecord.
+// Compute 'findings' (strongest/weakest runs) from a list of ScenarioSummary.
 // This extracts the ranking logic out of the UI component so pages can compute
 // results and pass plain data to presentational components.
-export function computeFindings(items: ScenarioRecord[], topN = 3): { strongest: ScenarioRecord[]; weakest: ScenarioRecord[] } {
+export function computeFindings(items: ScenarioSummary[], topN = 3): { strongest: ScenarioSummary[]; weakest: ScenarioSummary[] } {
   if (!Array.isArray(items) || items.length === 0) return { strongest: [], weakest: [] }
 
   function normalize(arr: number[]): (x: number) => number {
@@ -1,5 +1,5 @@
 import type { Session } from '../../types/domain';
-import type { ScenarioRecord } from '../../types/ipc';
+import type { ScenarioSummary } from '../../types/ipc';
 import { getScenarioName } from '../utils';
 
 export type MetricsSeries = { score: number[]; acc: number[]; ttk: number[] }
@@ -8,7 +8,7 @@ export type MetricsSeries = { score: number[]; acc: number[]; ttk: number[] }
  * Group runs by scenario name, collecting arrays for score, accuracy (%), and Real Avg TTK.
  * Runs are assumed newest-first coming in; resulting arrays are also newest-first.
  */
-export function groupByScenario(items: ScenarioRecord[]): Map<string, MetricsSeries> {
+export function groupByScenario(items: ScenarioSummary[]): Map<string, MetricsSeries> {
   const m = new Map<string, MetricsSeries>()
   for (const it of items) {
     const name = getScenarioName(it)
@@ -1,4 +1,4 @@
-import type { Point, ScenarioRecord } from '../../types/ipc'
+import type { KillEvent, Point, ScenarioRecord } from '../../types/ipc'
 import { formatNumber, formatPct } from '../utils'
 
 export type MouseKillEvent = {
@@ -49,11 +49,11 @@ export type SensSuggestion = {
 // Entry point used by UI
 export function computeMouseTraceAnalysis(item: ScenarioRecord): MouseTraceAnalysis | null {
   const points = Array.isArray(item.mouseTrace) ? item.mouseTrace : []
-  const events = Array.isArray(item.events) ? item.events : []
+  const events = Array.isArray(item.kills) ? item.kills : []
   if (points.length < 4 || events.length === 0) return null
   const baseIso = String((item.stats as any)?.['Date Played'] || '')
   if (!baseIso) return null
-  const kills = parseEventsToKills(events, baseIso)
+  const kills = toMouseKillEvents(events, baseIso)
   if (!kills.length) return null
 
   // Heuristic window cap based on shots per kill (median)
@@ -87,7 +87,7 @@ export function computeMouseTraceAnalysis(item: ScenarioRecord): MouseTraceAnaly
 }
 
 // --- Core helpers ---
-export function parseEventsToKills(events: string[][], baseIso: string): MouseKillEvent[] {
+export function toMouseKillEvents(events: KillEvent[], baseIso: string): MouseKillEvent[] {
   const out: MouseKillEvent[] = []
   const end = new Date(baseIso)
   // Use LOCAL date parts to avoid UTC day drift
@@ -95,10 +95,10 @@ export function parseEventsToKills(events: string[][], baseIso: string): MouseKi
   const baseM = end.getMonth() // 0-based
   const baseD = end.getDate()
   const endTOD = (end.getHours() * 3600) + (end.getMinutes() * 60) + end.getSeconds() + (end.getMilliseconds() / 1000)
-  for (const row of events) {
-    if (!row || row.length < 7) continue
-    const idx = toInt(row[0])
-    const todStr = row[1]
+  for (const ev of events) {
+    if (!ev) continue
+    const idx = ev.index
+    const todStr = ev.timestamp
     // Parse HH:MM:SS(.fff)
     const m = String(todStr || '').match(/^(\d{1,2}):(\d{2}):(\d{2})(?:\.(\d+))?$/)
     if (!m) continue
@@ -113,9 +113,9 @@ export function parseEventsToKills(events: string[][], baseIso: string): MouseKi
     if (Number.isFinite(endTOD) && tsSec > endTOD + 1) {
       evt.setDate(evt.getDate() - 1)
     }
-    const ttkSec = parseTTK(row[4])
-    const shots = toFloat(row[5])
-    const hits = toFloat(row[6])
+    const ttkSec = ev.ttk
+    const shots = ev.shots
+    const hits = ev.hits
     out.push({ idx, tsIso: evt.toISOString(), tsAbsMs: evt.getTime(), ttkSec, shots, hits })
   }
   // Enforce non-decreasing timestamps by slight monotonic fix (in case of equal ms)
@@ -218,12 +218,6 @@ export function analyzeWindow(points: Point[], win: { startMs: number; endMs: nu
 }
 
 // --- Utilities ---
-function parseTTK(s: any): number {
-  const m = String(s || '').match(/([0-9]*\.?[0-9]+)s/i)
-  return m ? parseFloat(m[1]) : NaN
-}
-function toInt(s: any): number { const n = parseInt(String(s || ''), 10); return Number.isFinite(n) ? n : NaN }
-function toFloat(s: any): number { const n = parseFloat(String(s || '')); return Number.isFinite(n) ? n : NaN }
 function tsMs(v: any): number { if (v == null) return 0; if (typeof v === 'number') return v; const n = Date.parse(String(v)); return Number.isFinite(n) ? n : 0 }
 function lowerBound(points: Point[], targetMs: number, lo = 0, hi = points.length - 1): number {
   let l = Math.max(0, lo), r = Math.max(l, hi)
@@ -1,4 +1,4 @@
-import type { ScenarioRecord } from '../../types/ipc'
+import type { ScenarioSummary } from '../../types/ipc'
 import { getDatePlayed, getScenarioName } from '../utils'
 
 export type HighscorePrediction = {
@@ -41,7 +41,7 @@ function humanizeETA(ms: number): string {
 }
 
 // Try to get a precise timestamp from fileName, else fall back to Date Played + Challenge Start
-export function parseRecordTimestamp(it: ScenarioRecord): number {
+export function parseRecordTimestamp(it: ScenarioSummary): number {
   const fn = String(it.fileName || '')
   const m = fn.match(/(\d{4})\.(\d{2})\.(\d{2})-(\d{2})\.(\d{2})\.(\d{2})/)
   if (m) {
@@ -111,7 +111,7 @@ function weightedLinReg(xs: number[], ys: number[], ws: number[]): { a: number;
   return { a, b, r2 }
 }
 
-export function collectScenarioHistory(items: ScenarioRecord[], name: string): Array<{ t: number; score: number; sessionId: number }> {
+export function collectScenarioHistory(items: ScenarioSummary[], name: string): Array<{ t: number; score: number; sessionId: number }> {
   // Group by sessions using a fixed gap threshold (minutes). If user changes app settings,
   // this can be wired later, but here we keep a sane default independent of UI store.
   const SESSION_GAP_MIN = 30
@@ -196,7 +196,7 @@ function findOptimalPauseAndRuns(deficit: number, fit: { a: number; b: number; t
   return best
 }
 
-export function predictNextHighscore(items: ScenarioRecord[], name: string): HighscorePrediction {
+export function predictNextHighscore(items: ScenarioSummary[], name: string): HighscorePrediction {
   const now = Date.now()
   const hist = collectScenarioHistory(items, name)
   const n = hist.length
@@ -182,13 +182,12 @@ function nearestNeighborMedian(points: Array<{ x: number; y: number }>): number
 }
 
 export function computeScenarioAnalysis(item: ScenarioRecord): ScenarioComputed {
-  // treat rows as kills (each row is a kill event)
-  const kills = Array.isArray(item.events) ? item.events : []
+  const kills = Array.isArray(item.kills) ? item.kills : []
   const secInDay = 86400
   const startStr = (item.stats as any)?.['Challenge Start']
   const startSecRaw = toSec(startStr)
   // prefer first kill timestamp as origin so first kill is at t=0
-  const firstKillSec = toSec(kills[0]?.[1])
+  const firstKillSec = toSec(kills[0]?.timestamp)
   let originSec = Number.isFinite(firstKillSec) ? firstKillSec : (Number.isFinite(startSecRaw) ? startSecRaw : 0)
 
   const labels: string[] = []
@@ -205,8 +204,8 @@ export function computeScenarioAnalysis(item: ScenarioRecord): ScenarioComputed
   let longestGap = 0
   let sumGap = 0
 
-  for (const row of kills) {
-    const ts = toSec(row[1])
+  for (const kill of kills) {
+    const ts = toSec(kill.timestamp)
     if (!Number.isFinite(ts)) continue
     // relative time since first kill (originSec)
     const rel = ts >= originSec ? (ts - originSec) : (ts + (secInDay - originSec))
@@ -229,8 +228,8 @@ export function computeScenarioAnalysis(item: ScenarioRecord): ScenarioComputed
       prevSec = ts
     }
 
-    const shots = parseFloat(row[5] || '0')
-    const hits = parseFloat(row[6] || '0')
+    const shots = kill.shots
+    const hits = kill.hits
     if (Number.isFinite(shots)) cumShots += shots
     if (Number.isFinite(hits)) cumHits += hits
     const acc = cumShots > 0 ? (cumHits / cumShots) : 0
@@ -1,10 +1,10 @@
 import type { Session } from '../../types/domain'
-import type { ScenarioRecord } from '../../types/ipc'
+import type { ScenarioSummary } from '../../types/ipc'
 import { getScenarioName } from '../utils'
 
 export type Metric = 'score' | 'acc'
 
-export function metricOf(rec: ScenarioRecord, metric: Metric): number {
+export function metricOf(rec: ScenarioSummary, metric: Metric): number {
   if (metric === 'score') {
     const v = Number(rec.stats['Score'] ?? 0)
     return Number.isFinite(v) ? v : 0
@@ -14,7 +14,7 @@ export function metricOf(rec: ScenarioRecord, metric: Metric): number {
   }
 }
 
-export function runDurationMs(rec: ScenarioRecord): number {
+export function runDurationMs(rec: ScenarioSummary): number {
   const datePlayedStr = String(rec.stats['Date Played'] ?? '')
   const end = Date.parse(datePlayedStr)
   const startTime = String(rec.stats['Challenge Start'] ?? '')
@@ -29,8 +29,8 @@ export function runDurationMs(rec: ScenarioRecord): number {
 }
 
 /** Returns an array of sessions; each contains ordered runs for the scenario (oldest -> newest). */
-export function collectRunsBySession(sessions: Session[], scenarioName: string): ScenarioRecord[][] {
-  const res: ScenarioRecord[][] = []
+export function collectRunsBySession(sessions: Session[], scenarioName: string): ScenarioSummary[][] {
+  const res: ScenarioSummary[][] = []
   for (const sess of sessions) {
     const items = sess.items.filter(it => getScenarioName(it) === scenarioName)
     if (items.length === 0) continue
@@ -41,7 +41,7 @@ export function collectRunsBySession(sessions: Session[], scenarioName: string):
   return res
 }
 
-export function expectedByIndex(runs: ScenarioRecord[][], metric: Metric): { mean: number[]; std: number[] } {
+export function expectedByIndex(runs: ScenarioSummary[][], metric: Metric): { mean: number[]; std: number[] } {
   const maxLen = runs.reduce((m, r) => Math.max(m, r.length), 0)
   const mean: number[] = []
   const std: number[] = []
@@ -58,7 +58,7 @@ export function expectedByIndex(runs: ScenarioRecord[][], metric: Metric): { mea
   return { mean, std }
 }
 
-export function expectedBestVsLength(runs: ScenarioRecord[][], metric: Metric): number[] {
+export function expectedBestVsLength(runs: ScenarioSummary[][], metric: Metric): number[] {
   const maxLen = runs.reduce((m, r) => Math.max(m, r.length), 0)
   const curve: number[] = []
   for (let L = 1; L <= maxLen; L++) {
@@ -88,7 +88,7 @@ export type LengthStats = {
  * prefix averages over the first L runs. This answers: "If I play L runs,
  * what is the typical average performance, and how wide is the spread?"
  */
-export function expectedAvgVsLength(runs: ScenarioRecord[][], metric: Metric): LengthStats {
+export function expectedAvgVsLength(runs: ScenarioSummary[][], metric: Metric): LengthStats {
   const maxLen = runs.reduce((m, r) => Math.max(m, r.length), 0)
   const mean: number[] = []
   const min: number[] = []
@@ -1,15 +1,25 @@
 import {
   CheckForUpdates as _CheckForUpdates,
+  CollectTraceGarbage as _CollectTraceGarbage,
+  DeleteTrace as _DeleteTrace,
   DownloadAndInstallUpdate as _DownloadAndInstallUpdate,
   GetBenchmarkProgress as _GetBenchmarkProgress,
   GetBenchmarks as _GetBenchmarks,
   GetDefaultSettings as _GetDefaultSettings,
   GetFavoriteBenchmarks as _GetFavoriteBenchmarks,
   GetRecentScenarios as _GetRecentScenarios,
+  GetScenarioDetail as _GetScenarioDetail,
+  GetSessions as _GetSessions,
   GetSettings as _GetSettings,
+  GetSettingsRecovery as _GetSettingsRecovery,
+  GetTraceStats as _GetTraceStats,
   GetVersion as _GetVersion,
+  ImportStatsArchive as _ImportStatsArchive,
   LaunchKovaaksPlaylist as _LaunchKovaaksPlaylist,
   LaunchKovaaksScenario as _LaunchKovaaksScenario,
+  ListTraces as _ListTraces,
+  MigrateTraces as _MigrateTraces,
+  QueryScenarios as _QueryScenarios,
   ResetSettings as _ResetSettings,
   SetFavoriteBenchmarks as _SetFavoriteBenchmarks,
   StartWatcher as _StartWatcher,
@@ -17,7 +27,8 @@ import {
   UpdateSettings as _UpdateSettings
 } from '../../wailsjs/go/main/App'
 import type { models } from '../../wailsjs/go/models'
-import type { Benchmark, BenchmarkProgress, ScenarioRecord, Settings, UpdateInfo } from '../types/ipc'
+import type { Session } from '../types/domain'
+import type { Benchmark, BenchmarkProgress, ImportProgress, ScenarioPage, ScenarioQuery, ScenarioRecord, ScenarioSummary, Settings, TraceGCReport, TraceInfo, TraceMigrationReport, TraceStats, UpdateInfo } from '../types/ipc'
 
 export type { models }
 
@@ -37,9 +48,62 @@ export async function stopWatcher(): Promise<void> {
   }
 }
 
-export async function getRecentScenarios(limit: number): Promise<ScenarioRecord[]> {
+export async function getRecentScenarios(limit: number): Promise<ScenarioSummary[]> {
   const res = await _GetRecentScenarios(limit)
-  return (Array.isArray(res) ? res : []) as unknown as ScenarioRecord[]
+  return (Array.isArray(res) ? res : []) as unknown as ScenarioSummary[]
+}
+
+// Full record for one run; kill events and the mouse trace are loaded on demand
+export async function getScenarioDetail(filePath: string): Promise<ScenarioRecord> {
+  const res = await _GetScenarioDetail(String(filePath || ''))
+  return res as unknown as ScenarioRecord
+}
+
+// Filtered, sorted page over the full scenario history (total counts all matches)
+export async function queryScenarios(q: ScenarioQuery): Promise<ScenarioPage> {
+  const res = await _QueryScenarios(q as unknown as models.ScenarioQuery)
+  const page = (res ?? {}) as unknown as ScenarioPage
+  return { total: Number(page.total) || 0, offset: Number(page.offset) || 0, items: Array.isArray(page.items) ? page.items : [] }
+}
+
+// Sessions grouped by the backend with the configured gap; bounds are RFC3339 ('' = open)
+export async function getSessions(from = '', to = ''): Promise<Session[]> {
+  const res = await _GetSessions(from, to)
+  return (Array.isArray(res) ? res : []) as unknown as Session[]
+}
+
+// Import runs from a .zip/.tar.gz backup of a Kovaak's stats folder into a
+// stats source (path or label; empty for the primary one)
+export async function importStatsArchive(path: string, source = ''): Promise<ImportProgress> {
+  const res = await _ImportStatsArchive(String(path || ''), String(source || ''))
+  return res as unknown as ImportProgress
+}
+
+// Apply the trace retention settings now; reports what was deleted
+export async function collectTraceGarbage(): Promise<TraceGCReport> {
+  const res = await _CollectTraceGarbage()
+  return res as unknown as TraceGCReport
+}
+
+// Stored mouse traces (metadata only), most recent first
+export async function listTraces(): Promise<TraceInfo[]> {
+  const res = await _ListTraces()
+  return (Array.isArray(res) ? res : []) as unknown as TraceInfo[]
+}
+
+export async function deleteTrace(fileName: string): Promise<void> {
+  await _DeleteTrace(String(fileName || ''))
+}
+
+// Rewrite traces stored in older data versions or as JSON in the current format
+export async function migrateTraces(): Promise<TraceMigrationReport> {
+  const res = await _MigrateTraces()
+  return res as unknown as TraceMigrationReport
+}
+
+export async function getTraceStats(): Promise<TraceStats> {
+  const res = await _GetTraceStats()
+  return res as unknown as TraceStats
 }
 
 export async function getSettings(): Promise<Settings> {
@@ -47,6 +111,12 @@ export async function getSettings(): Promise<Settings> {
   return s as unknown as Settings
 }
 
+// Message shown when settings were restored from a backup (or reset) at startup; '' otherwise
+export async function getSettingsRecovery(): Promise<string> {
+  const res = await _GetSettingsRecovery()
+  return String(res || '')
+}
+
 export async function getDefaultSettings(): Promise<Settings> {
   const s = await _GetDefaultSettings()
   return s as unknown as Settings
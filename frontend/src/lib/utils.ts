@@ -1,8 +1,8 @@
-import type { ScenarioRecord } from '../types/ipc';
+import type { ScenarioSummary } from '../types/ipc';
 
 export const MISSING_STR = 'N/A'
 
-export function getScenarioName(it: ScenarioRecord | { fileName?: string; stats?: Record<string, any> }): string {
+export function getScenarioName(it: ScenarioSummary | { fileName?: string; stats?: Record<string, any> }): string {
   const stats = (it as any).stats as Record<string, any> | undefined
   const direct = stats?.['Scenario']
   if (typeof direct === 'string' && direct.trim().length > 0) return direct
@@ -6,9 +6,9 @@ import { ListDetail, Tabs } from '../../components'
 import { usePageState } from '../../hooks/usePageState'
 import { useStore } from '../../hooks/useStore'
 import { useUIState } from '../../hooks/useUIState'
-import { getSettings, launchScenario } from '../../lib/internal'
+import { getScenarioDetail, getSettings, launchScenario } from '../../lib/internal'
 import { formatPct01, getDatePlayed, getScenarioName } from '../../lib/utils'
-import type { ScenarioRecord } from '../../types/ipc'
+import type { ScenarioRecord, ScenarioSummary } from '../../types/ipc'
 import { AiTab, AnalysisTab, MouseTraceTab, RawTab } from './tabs'
 
 export function ScenariosPage() {
@@ -96,16 +96,39 @@ export function ScenariosPage() {
               </button>
             </div>
           ) : null}
-          detail={<ScenarioDetail item={active ?? null} />}
+          detail={<ScenarioDetail summary={active ?? null} />}
         />
       </div>
     </div>
   )
 }
 
-function ScenarioDetail({ item }: { item: ScenarioRecord | null }) {
+function ScenarioDetail({ summary }: { summary: ScenarioSummary | null }) {
   const [tab, setTab] = useUIState<'raw' | 'analysis' | 'mouse' | 'ai'>('tabs:scenario', 'raw')
   const [sp, setSp] = useSearchParams()
+  const [item, setItem] = useState<ScenarioRecord | null>(null)
+  const filePath = summary?.filePath ?? ''
+  // Lists only carry summaries; fetch the full record (kills, trace) for the selected run
+  useEffect(() => {
+    let cancelled = false
+    const load = () => {
+      if (!filePath) { setItem(null); return }
+      getScenarioDetail(filePath)
+        .then(rec => { if (!cancelled) setItem(rec) })
+        .catch(() => { if (!cancelled) setItem(null) })
+    }
+    load()
+    let off: (() => void) | null = null
+    try {
+      off = EventsOn('ScenarioUpdated', (data: any) => {
+        if (data && data.filePath === filePath) load()
+      })
+    } catch { /* ignore */ }
+    return () => {
+      cancelled = true
+      try { off && off() } catch { /* ignore */ }
+    }
+  }, [filePath, summary?.hasTrace])
   useEffect(() => {
     // Keep tab in sync with URL if present
     const t = sp.get('tab')
@@ -113,7 +136,8 @@ function ScenarioDetail({ item }: { item: ScenarioRecord | null }) {
       setTab(t as any)
     }
   }, [sp])
-  if (!item) return <div className="text-sm text-[var(--text-secondary)]">No scenario selected.</div>
+  if (!summary) return <div className="text-sm text-[var(--text-secondary)]">No scenario selected.</div>
+  if (!item || item.filePath !== filePath) return <div className="text-sm text-[var(--text-secondary)]">Loading…</div>
   const tabs = [
     { id: 'raw', label: 'Raw Stats', content: <RawTab item={item} /> },
     { id: 'analysis', label: 'Analysis', content: <AnalysisTab item={item} /> },
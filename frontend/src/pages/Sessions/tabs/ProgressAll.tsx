@@ -1,10 +1,12 @@
-import { useEffect, useMemo } from 'react'
+import { useEffect, useMemo, useState } from 'react'
 import { ChartBox, MetricsControls, MetricsLineChart, NextHighscoreForecast, PerformanceVsSensChart, SessionLengthInsights, SummaryStats, TimeOfDayAreaChart } from '../../../components'
 import { usePageState } from '../../../hooks/usePageState'
 import { useStore } from '../../../hooks/useStore'
 import { predictNextHighscore } from '../../../lib/analysis'
 import { buildChartSeries, computeSessionAverages, groupByScenario } from '../../../lib/analysis/metrics'
+import { queryScenarios } from '../../../lib/internal'
 import { getScenarioName } from '../../../lib/utils'
+import type { ScenarioSummary } from '../../../types/ipc'
 
 export function ProgressAllTab() {
   // All scenarios across all sessions (newest first in store)
@@ -37,7 +39,19 @@ export function ProgressAllTab() {
     }
   }, [names, selectedName])
 
-  const metricsRuns = byName.get(selectedName) ?? { score: [], acc: [], ttk: [] }
+  // The store only holds recent runs; fetch the selected scenario's full history from the backend
+  const [history, setHistory] = useState<ScenarioSummary[]>([])
+  useEffect(() => {
+    let cancelled = false
+    if (!selectedName) { setHistory([]); return }
+    queryScenarios({ name: selectedName, sortBy: 'date', limit: 1000 })
+      .then(page => { if (!cancelled) setHistory(page.items) })
+      .catch(() => { if (!cancelled) setHistory([]) })
+    return () => { cancelled = true }
+  }, [selectedName, scenarios])
+  const historyByName = useMemo(() => groupByScenario(history), [history])
+
+  const metricsRuns = historyByName.get(selectedName) ?? byName.get(selectedName) ?? { score: [], acc: [], ttk: [] }
   const metricsSessions = useMemo(() => computeSessionAverages(sessions, selectedName), [sessions, selectedName])
 
   const metrics = mode === 'sessions' ? metricsSessions : metricsRuns
@@ -103,7 +117,7 @@ export function ProgressAllTab() {
 
       <SummaryStats title="Progress summary" score={metrics.score} acc={metrics.acc} ttk={metrics.ttk} firstPct={firstPct} lastPct={lastPct} onFirstPct={setFirstPct} onLastPct={setLastPct} />
 
-      <NextHighscoreForecast pred={useMemo(() => predictNextHighscore(scenarios, selectedName), [scenarios, selectedName])} />
+      <NextHighscoreForecast pred={useMemo(() => predictNextHighscore(history.length > 0 ? history : scenarios, selectedName), [history, scenarios, selectedName])} />
 
       <SessionLengthInsights sessions={sessions} scenarioName={selectedName} />
 
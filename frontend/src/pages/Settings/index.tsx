@@ -3,13 +3,13 @@ import { useEffect, useState } from 'react'
 import { BrowserOpenURL } from '../../../wailsjs/runtime'
 import { Button, Dropdown } from '../../components'
 import { useStore } from '../../hooks/useStore'
-import { checkForUpdates, downloadAndInstallUpdate, getSettings, getVersion, resetSettings, updateSettings } from '../../lib/internal'
+import { checkForUpdates, collectTraceGarbage, deleteTrace, downloadAndInstallUpdate, getSessions, getSettings, getTraceStats, getVersion, listTraces, migrateTraces, resetSettings, updateSettings } from '../../lib/internal'
 import { applyTheme, getSavedTheme, setTheme, THEMES, type Theme } from '../../lib/theme'
-import { MISSING_STR } from '../../lib/utils'
-import type { Settings, UpdateInfo } from '../../types/ipc'
+import { formatDuration, MISSING_STR } from '../../lib/utils'
+import type { Settings, TraceInfo, TraceStats, UpdateInfo } from '../../types/ipc'
 
 export function SettingsPage() {
-  const setSessionGap = useStore(s => s.setSessionGap)
+  const setSessions = useStore(s => s.setSessions)
   const [steamDir, setSteamDir] = useState('')
   const [steamIdOverride, setSteamIdOverride] = useState('')
   const [statsPath, setStatsPath] = useState('')
@@ -19,6 +19,17 @@ export function SettingsPage() {
   const [mouseEnabled, setMouseEnabled] = useState(false)
   const [mouseBuffer, setMouseBuffer] = useState(10)
   const [maxExisting, setMaxExisting] = useState(500)
+  const [statsTimezone, setStatsTimezone] = useState('')
+  const [apiEnabled, setApiEnabled] = useState(false)
+  const [apiPort, setApiPort] = useState(47811)
+  const [apiToken, setApiToken] = useState('')
+  const [overlayEnabled, setOverlayEnabled] = useState(false)
+  const [overlayDir, setOverlayDir] = useState('')
+  const [traceMaxAge, setTraceMaxAge] = useState(0)
+  const [traceMaxMB, setTraceMaxMB] = useState(0)
+  const [traceKeepBest, setTraceKeepBest] = useState(0)
+  const [traceOrphans, setTraceOrphans] = useState(false)
+  const [gcStatus, setGcStatus] = useState('')
   const [showAdvanced, setShowAdvanced] = useState(false)
   // Updates state
   const [currentVersion, setCurrentVersion] = useState<string>("")
@@ -41,6 +52,22 @@ export function SettingsPage() {
         setMouseEnabled(Boolean(s.mouseTrackingEnabled))
         setMouseBuffer(Number(s.mouseBufferMinutes))
         setMaxExisting(Number((s as any).maxExistingOnStart))
+        setStatsTimezone(s.statsTimezone || '')
+        setApiEnabled(Boolean(s.apiEnabled))
+        setApiPort(Number(s.apiPort) || 47811)
+        setApiToken(s.apiToken || '')
+      setOverlayEnabled(Boolean(s.overlayEnabled))
+      setOverlayDir(s.overlayDir || '')
+      setTraceMaxAge(Number(s.traceMaxAgeDays) || 0)
+      setTraceMaxMB(Number(s.traceMaxTotalMB) || 0)
+      setTraceKeepBest(Number(s.traceKeepBest) || 0)
+      setTraceOrphans(Boolean(s.traceDeleteOrphans))
+        setOverlayEnabled(Boolean(s.overlayEnabled))
+        setOverlayDir(s.overlayDir || '')
+        setTraceMaxAge(Number(s.traceMaxAgeDays) || 0)
+        setTraceMaxMB(Number(s.traceMaxTotalMB) || 0)
+        setTraceKeepBest(Number(s.traceKeepBest) || 0)
+        setTraceOrphans(Boolean(s.traceDeleteOrphans))
       })
       .catch(() => { })
     // Load current version for display
@@ -48,15 +75,28 @@ export function SettingsPage() {
   }, [])
 
   const save = async () => {
-    const payload: Settings = { steamInstallDir: steamDir, steamIdOverride, statsDir: statsPath, tracesDir: tracesPath, sessionGapMinutes: gap, theme, mouseTrackingEnabled: mouseEnabled, mouseBufferMinutes: mouseBuffer, maxExistingOnStart: maxExisting }
+    const payload: Settings = { steamInstallDir: steamDir, steamIdOverride, statsDir: statsPath, tracesDir: tracesPath, sessionGapMinutes: gap, theme, mouseTrackingEnabled: mouseEnabled, mouseBufferMinutes: mouseBuffer, maxExistingOnStart: maxExisting, statsTimezone: statsTimezone.trim(), apiEnabled, apiPort, apiToken: apiToken.trim(), overlayEnabled, overlayDir: overlayDir.trim(), traceMaxAgeDays: traceMaxAge, traceMaxTotalMB: traceMaxMB, traceKeepBest, traceDeleteOrphans: traceOrphans }
     try {
       await updateSettings(payload)
+      // The backend generates the API token when it is enabled without one
+      const saved = await getSettings()
+      setApiToken(saved?.apiToken || '')
       setTheme(theme)
-      setSessionGap(gap)
+      // Sessions are regrouped by the backend with the new gap
+      setSessions(await getSessions())
     } catch (e) {
       console.error('UpdateSettings error:', e)
     }
   }
+  const onCleanTraces = async () => {
+    setGcStatus('Cleaning…')
+    try {
+      const r = await collectTraceGarbage()
+      setGcStatus(`Deleted ${r.deleted} traces (${(r.freedBytes / 1048576).toFixed(1)} MB), ${r.remaining} left`)
+    } catch (e) {
+      setGcStatus((e as Error)?.message || 'Trace cleanup failed')
+    }
+  }
   const onReset = async () => {
     try {
       await resetSettings()
@@ -71,6 +111,10 @@ export function SettingsPage() {
       setMouseEnabled(Boolean(s.mouseTrackingEnabled))
       setMouseBuffer(Number(s.mouseBufferMinutes))
       setMaxExisting(Number((s as any).maxExistingOnStart))
+      setStatsTimezone(s.statsTimezone || '')
+      setApiEnabled(Boolean(s.apiEnabled))
+      setApiPort(Number(s.apiPort) || 47811)
+      setApiToken(s.apiToken || '')
     } catch (e) {
       console.error('ResetSettings error:', e)
     }
@@ -221,6 +265,14 @@ export function SettingsPage() {
                   className="w-full px-2 py-1 rounded bg-[var(--bg-tertiary)] border border-[var(--border-primary)]"
                 />
               </Field>
+              <Field label="Stats timezone (IANA, e.g. Europe/Berlin; blank = this PC)">
+                <input
+                  value={statsTimezone}
+                  onChange={e => setStatsTimezone(e.target.value)}
+                  placeholder="Local time"
+                  className="w-full px-2 py-1 rounded bg-[var(--bg-tertiary)] border border-[var(--border-primary)]"
+                />
+              </Field>
               <Field label="Traces directory">
                 <input
                   value={tracesPath}
@@ -244,6 +296,83 @@ export function SettingsPage() {
                   className="w-24 px-2 py-1 rounded bg-[var(--bg-tertiary)] border border-[var(--border-primary)]"
                 />
               </Field>
+              <Field label="Local HTTP API (127.0.0.1)">
+                <Dropdown
+                  value={apiEnabled ? 'on' : 'off'}
+                  onChange={(v: string) => setApiEnabled(v === 'on')}
+                  options={[{ label: 'On', value: 'on' }, { label: 'Off', value: 'off' }]}
+                  size="md"
+                />
+              </Field>
+              <Field label="API port">
+                <input
+                  type="number"
+                  value={apiPort}
+                  onChange={e => setApiPort(Math.min(65535, Math.max(1, Number(e.target.value))))}
+                  className="w-24 px-2 py-1 rounded bg-[var(--bg-tertiary)] border border-[var(--border-primary)]"
+                />
+              </Field>
+              <Field label="API token (blank = generate)">
+                <input
+                  value={apiToken}
+                  onChange={e => setApiToken(e.target.value)}
+                  className="w-full px-2 py-1 rounded font-mono bg-[var(--bg-tertiary)] border border-[var(--border-primary)]"
+                />
+              </Field>
+              <Field label="Stream overlay files">
+                <Dropdown
+                  value={overlayEnabled ? 'on' : 'off'}
+                  onChange={(v: string) => setOverlayEnabled(v === 'on')}
+                  options={[{ label: 'On', value: 'on' }, { label: 'Off', value: 'off' }]}
+                  size="md"
+                />
+              </Field>
+              <Field label="Overlay folder">
+                <input
+                  value={overlayDir}
+                  onChange={e => setOverlayDir(e.target.value)}
+                  className="w-full px-2 py-1 rounded bg-[var(--bg-tertiary)] border border-[var(--border-primary)]"
+                />
+              </Field>
+              <Field label="Delete traces older than (days, 0 = never)">
+                <input
+                  type="number"
+                  value={traceMaxAge}
+                  onChange={e => setTraceMaxAge(Math.max(0, Number(e.target.value)))}
+                  className="w-24 px-2 py-1 rounded bg-[var(--bg-tertiary)] border border-[var(--border-primary)]"
+                />
+              </Field>
+              <Field label="Traces size limit (MB, 0 = none)">
+                <input
+                  type="number"
+                  value={traceMaxMB}
+                  onChange={e => setTraceMaxMB(Math.max(0, Number(e.target.value)))}
+                  className="w-24 px-2 py-1 rounded bg-[var(--bg-tertiary)] border border-[var(--border-primary)]"
+                />
+              </Field>
+              <Field label="Keep best traced runs per scenario (0 = all)">
+                <input
+                  type="number"
+                  value={traceKeepBest}
+                  onChange={e => setTraceKeepBest(Math.max(0, Number(e.target.value)))}
+                  className="w-24 px-2 py-1 rounded bg-[var(--bg-tertiary)] border border-[var(--border-primary)]"
+                />
+              </Field>
+              <Field label="Delete traces without stats file">
+                <Dropdown
+                  value={traceOrphans ? 'on' : 'off'}
+                  onChange={(v: string) => setTraceOrphans(v === 'on')}
+                  options={[{ label: 'On', value: 'on' }, { label: 'Off', value: 'off' }]}
+                  size="md"
+                />
+              </Field>
+              <Field label="Trace cleanup">
+                <div className="flex items-center gap-2">
+                  <Button variant="secondary" size="sm" onClick={onCleanTraces}>Clean up now</Button>
+                  {gcStatus && <span className="text-xs text-[var(--text-secondary)]">{gcStatus}</span>}
+                </div>
+              </Field>
+              <TraceStore refreshKey={gcStatus} />
             </div>
           )}
         </section>
@@ -271,4 +400,86 @@ function Field({ label, children }: FieldProps) {
   )
 }
 
+function formatMB(bytes: number): string {
+  return `${(Number(bytes) / 1048576).toFixed(1)} MB`
+}
+
+// Stored mouse traces with per-run deletion. Reloads when refreshKey changes.
+function TraceStore({ refreshKey }: { refreshKey: string }) {
+  const [stats, setStats] = useState<TraceStats | null>(null)
+  const [items, setItems] = useState<TraceInfo[]>([])
+  const [open, setOpen] = useState(false)
+
+  const load = async () => {
+    try {
+      setStats(await getTraceStats())
+      if (open) setItems(await listTraces())
+    } catch (e) {
+      console.error('Trace store error:', e)
+    }
+  }
+  useEffect(() => { load() }, [refreshKey, open])
+
+  const [migrateStatus, setMigrateStatus] = useState('')
+  const onMigrate = async () => {
+    try {
+      const r = await migrateTraces()
+      setMigrateStatus(`Upgraded ${r.upgraded} of ${r.checked}${r.newer ? `, ${r.newer} need a newer version` : ''}${r.failed ? `, ${r.failed} failed` : ''}`)
+      await load()
+    } catch (e) {
+      setMigrateStatus((e as Error)?.message || 'Upgrade failed')
+    }
+  }
+
+  const onDelete = async (t: TraceInfo) => {
+    try {
+      await deleteTrace(t.traceFile)
+      await load()
+    } catch (e) {
+      console.error('DeleteTrace error:', e)
+    }
+  }
+
+  return (
+    <div className="space-y-2">
+      <Field label="Stored traces">
+        <div className="flex items-center gap-2 text-sm">
+          <span>
+            {stats ? `${stats.count} runs · ${stats.scenarios} scenarios · ${formatMB(stats.totalBytes)} · ${formatDuration(stats.totalDurationMs)}` : MISSING_STR}
+            {stats && stats.unreadable > 0 ? ` · ${stats.unreadable} unreadable` : ''}
+          </span>
+          <Button variant="secondary" size="sm" onClick={() => setOpen(!open)}>{open ? 'Hide' : 'Show'}</Button>
+          {stats && stats.legacy > 0 && (
+            <Button variant="secondary" size="sm" onClick={onMigrate}>Upgrade {stats.legacy} old</Button>
+          )}
+          {migrateStatus && <span className="text-xs text-[var(--text-secondary)]">{migrateStatus}</span>}
+        </div>
+      </Field>
+      {open && (
+        <div className="max-h-64 overflow-auto rounded border border-[var(--border-primary)]">
+          <table className="w-full text-xs">
+            <tbody>
+              {items.map(t => (
+                <tr key={t.traceFile} className="border-b border-[var(--border-primary)]">
+                  <td className="px-2 py-1">{t.scenarioName || t.fileName}</td>
+                  <td className="px-2 py-1 text-[var(--text-secondary)]">{t.error ? 'unreadable' : new Date(t.datePlayed).toLocaleString()}</td>
+                  <td className="px-2 py-1 text-right">{t.points.toLocaleString()} pts</td>
+                  <td className="px-2 py-1 text-right">{formatDuration(t.durationMs)}</td>
+                  <td className="px-2 py-1 text-right">{formatMB(t.bytes)}</td>
+                  <td className="px-2 py-1 text-right">
+                    <Button variant="secondary" size="sm" onClick={() => onDelete(t)}>Delete</Button>
+                  </td>
+                </tr>
+              ))}
+              {items.length === 0 && (
+                <tr><td className="px-2 py-1 text-[var(--text-secondary)]">No traces stored.</td></tr>
+              )}
+            </tbody>
+          </table>
+        </div>
+      )}
+    </div>
+  )
+}
+
 export default SettingsPage
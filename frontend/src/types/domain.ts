@@ -1,8 +1,8 @@
-import type { ScenarioRecord } from './ipc'
+import type { ScenarioSummary } from './ipc'
 
 export interface Session {
   id: string
   start: string // ISO timestamp of first scenario in session
   end: string   // ISO timestamp of last scenario
-  items: ScenarioRecord[]
+  items: ScenarioSummary[]
 }
@@ -5,12 +5,111 @@ export type Point = {
   buttons?: number
 }
 
+export interface ScenarioStats {
+  score: number
+  hitCount: number
+  missCount: number
+  challengeStart: string
+  sensScale: string
+  horizSens: number
+  vertSens: number
+  dpi: number
+  fov: number
+  resolution: string
+  avgFps: number
+  pauseCount: number
+  pauseDuration: number
+  gameVersion: string
+  hash: string
+  accuracy: number
+  realAvgTtk: number
+  cm360: number
+}
+
+export interface KillEvent {
+  index: number
+  timestamp: string
+  bot: string
+  weapon: string
+  ttk: number
+  shots: number
+  hits: number
+  accuracy: number
+  damageDone: number
+  damagePossible: number
+  efficiency: number
+  cheated: boolean
+  overshots: number
+}
+
+export interface WeaponSummary {
+  weapon: string
+  shots: number
+  hits: number
+  damageDone: number
+  damagePossible: number
+  accuracy: number
+  efficiency: number
+}
+
 export interface ScenarioRecord {
   filePath: string
   fileName: string
+  mode: string
+  source?: string
+  contentHash?: string
+  playedAt: string // run end, UTC RFC3339
   stats: Record<string, any>
+  scenarioStats: ScenarioStats
   events: string[][]
-  mouseTrace?: Array<Point>
+  kills: KillEvent[]
+  weapons: WeaponSummary[]
+  killCount: number
+  startedAt?: string // run start, UTC RFC3339
+  hasTrace: boolean
+  mouseTrace?: Array<Point> // only filled by getScenarioDetail
+}
+
+// Lightweight list view of a ScenarioRecord (no events, kills or trace)
+export interface ScenarioSummary {
+  filePath: string
+  fileName: string
+  scenarioName: string
+  mode: string
+  source?: string
+  datePlayed: string
+  playedAt: string
+  stats: Record<string, any>
+  scenarioStats: ScenarioStats
+  killCount: number
+  hasTrace: boolean
+}
+
+export interface ScenarioQuery {
+  name?: string // substring, or glob when it contains * ? [
+  from?: string // RFC3339
+  to?: string
+  minCm360?: number
+  maxCm360?: number
+  mode?: string
+  sessionId?: string
+  tags?: string[]
+  minScore?: number
+  sortBy?: 'date' | 'score' | 'accuracy' | 'cm360' | 'name'
+  ascending?: boolean
+  offset?: number
+  limit?: number
+}
+
+export interface ScenarioPage {
+  total: number
+  offset: number
+  items: ScenarioSummary[]
+}
+
+export interface ScenarioRemoved {
+  filePath: string
+  fileName: string
 }
 
 export interface BenchmarkDifficulty {
@@ -72,6 +171,26 @@ export interface Settings {
   mouseTrackingEnabled?: boolean
   mouseBufferMinutes?: number
   maxExistingOnStart?: number
+  statsTimezone?: string // IANA zone of statsDir timestamps; empty = local
+  statsSources?: StatsSource[]
+  scenarioTags?: Record<string, string[]>
+  apiEnabled?: boolean // local HTTP API on 127.0.0.1:apiPort
+  apiPort?: number
+  apiToken?: string
+  overlayEnabled?: boolean // write overlay text files for OBS
+  overlayDir?: string
+  overlayFormats?: Record<string, string> // file name -> Go template
+  traceMaxAgeDays?: number // trace retention; 0 disables a rule
+  traceMaxTotalMB?: number
+  traceKeepBest?: number // best N traced runs kept per scenario
+  traceDeleteOrphans?: boolean
+}
+
+export interface StatsSource {
+  path: string
+  label?: string
+  recursive?: boolean
+  timezone?: string
 }
 
 export interface UpdateInfo {
@@ -81,3 +200,57 @@ export interface UpdateInfo {
   downloadUrl?: string
   releaseNotes?: string
 }
+
+export interface TraceGCReport {
+  deleted: number
+  freedBytes: number
+  orphaned: number
+  expired: number
+  beyondBest: number
+  overBudget: number
+  corrupt: number // damaged traces replaced by their backup
+  failed: number
+  remaining: number
+  remainingBytes: number
+}
+
+export interface TraceInfo {
+  fileName: string // stats file the trace belongs to
+  traceFile: string
+  scenarioName?: string
+  datePlayed: string // RFC3339
+  points: number
+  durationMs: number
+  bytes: number
+  version: number
+  legacy?: boolean // still stored as JSON
+  error?: string // set when unreadable
+}
+
+export interface TraceMigrationReport {
+  checked: number
+  upgraded: number
+  newer: number // written by a newer app version; left untouched
+  failed: number
+}
+
+export interface TraceStats {
+  count: number
+  scenarios: number
+  totalBytes: number
+  totalPoints: number
+  totalDurationMs: number
+  legacy: number
+  unreadable: number
+  oldest?: string
+  newest?: string
+}
+
+export interface ImportProgress {
+  archive: string
+  processed: number
+  imported: number
+  duplicates: number
+  failed: number
+  done: boolean
+}
@@ -4,6 +4,10 @@ import {models} from '../models';
 
 export function CheckForUpdates():Promise<models.UpdateInfo>;
 
+export function CollectTraceGarbage():Promise<models.TraceGCReport>;
+
+export function DeleteTrace(arg1:string):Promise<void>;
+
 export function DownloadAndInstallUpdate(arg1:string):Promise<boolean|string>;
 
 export function GetBenchmarkProgress(arg1:number):Promise<models.BenchmarkProgress>;
@@ -14,16 +18,32 @@ export function GetDefaultSettings():Promise<models.Settings>;
 
 export function GetFavoriteBenchmarks():Promise<Array<string>>;
 
-export function GetRecentScenarios(arg1:number):Promise<Array<models.ScenarioRecord>>;
+export function GetRecentScenarios(arg1:number):Promise<Array<models.ScenarioSummary>>;
+
+export function GetScenarioDetail(arg1:string):Promise<models.ScenarioRecord>;
+
+export function GetSessions(arg1:string,arg2:string):Promise<Array<models.Session>>;
 
 export function GetSettings():Promise<models.Settings>;
 
+export function GetSettingsRecovery():Promise<string>;
+
+export function GetTraceStats():Promise<models.TraceStats>;
+
 export function GetVersion():Promise<string>;
 
+export function ImportStatsArchive(arg1:string,arg2:string):Promise<models.ImportProgress>;
+
 export function LaunchKovaaksPlaylist(arg1:string):Promise<boolean|string>;
 
 export function LaunchKovaaksScenario(arg1:string,arg2:string):Promise<boolean|string>;
 
+export function ListTraces():Promise<Array<models.TraceInfo>>;
+
+export function MigrateTraces():Promise<models.TraceMigrationReport>;
+
+export function QueryScenarios(arg1:models.ScenarioQuery):Promise<models.ScenarioPage>;
+
 export function ResetSettings():Promise<boolean|string>;
 
 export function SetFavoriteBenchmarks(arg1:Array<string>):Promise<boolean|string>;
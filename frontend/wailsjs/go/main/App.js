@@ -6,6 +6,14 @@ export function CheckForUpdates() {
   return window['go']['main']['App']['CheckForUpdates']();
 }
 
+export function CollectTraceGarbage() {
+  return window['go']['main']['App']['CollectTraceGarbage']();
+}
+
+export function DeleteTrace(arg1) {
+  return window['go']['main']['App']['DeleteTrace'](arg1);
+}
+
 export function DownloadAndInstallUpdate(arg1) {
   return window['go']['main']['App']['DownloadAndInstallUpdate'](arg1);
 }
@@ -30,14 +38,34 @@ export function GetRecentScenarios(arg1) {
   return window['go']['main']['App']['GetRecentScenarios'](arg1);
 }
 
+export function GetScenarioDetail(arg1) {
+  return window['go']['main']['App']['GetScenarioDetail'](arg1);
+}
+
+export function GetSessions(arg1, arg2) {
+  return window['go']['main']['App']['GetSessions'](arg1, arg2);
+}
+
 export function GetSettings() {
   return window['go']['main']['App']['GetSettings']();
 }
 
+export function GetSettingsRecovery() {
+  return window['go']['main']['App']['GetSettingsRecovery']();
+}
+
+export function GetTraceStats() {
+  return window['go']['main']['App']['GetTraceStats']();
+}
+
 export function GetVersion() {
   return window['go']['main']['App']['GetVersion']();
 }
 
+export function ImportStatsArchive(arg1, arg2) {
+  return window['go']['main']['App']['ImportStatsArchive'](arg1, arg2);
+}
+
 export function LaunchKovaaksPlaylist(arg1) {
   return window['go']['main']['App']['LaunchKovaaksPlaylist'](arg1);
 }
@@ -46,6 +74,18 @@ export function LaunchKovaaksScenario(arg1, arg2) {
   return window['go']['main']['App']['LaunchKovaaksScenario'](arg1, arg2);
 }
 
+export function ListTraces() {
+  return window['go']['main']['App']['ListTraces']();
+}
+
+export function MigrateTraces() {
+  return window['go']['main']['App']['MigrateTraces']();
+}
+
+export function QueryScenarios(arg1) {
+  return window['go']['main']['App']['QueryScenarios'](arg1);
+}
+
 export function ResetSettings() {
   return window['go']['main']['App']['ResetSettings']();
 }
@@ -267,6 +267,64 @@ export namespace models {
 		}
 	}
 	
+	export class ImportProgress {
+	    archive: string;
+	    processed: number;
+	    imported: number;
+	    duplicates: number;
+	    failed: number;
+	    done: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new ImportProgress(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.archive = source["archive"];
+	        this.processed = source["processed"];
+	        this.imported = source["imported"];
+	        this.duplicates = source["duplicates"];
+	        this.failed = source["failed"];
+	        this.done = source["done"];
+	    }
+	}
+	export class KillEvent {
+	    index: number;
+	    timestamp: string;
+	    bot: string;
+	    weapon: string;
+	    ttk: number;
+	    shots: number;
+	    hits: number;
+	    accuracy: number;
+	    damageDone: number;
+	    damagePossible: number;
+	    efficiency: number;
+	    cheated: boolean;
+	    overshots: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new KillEvent(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.index = source["index"];
+	        this.timestamp = source["timestamp"];
+	        this.bot = source["bot"];
+	        this.weapon = source["weapon"];
+	        this.ttk = source["ttk"];
+	        this.shots = source["shots"];
+	        this.hits = source["hits"];
+	        this.accuracy = source["accuracy"];
+	        this.damageDone = source["damageDone"];
+	        this.damagePossible = source["damagePossible"];
+	        this.efficiency = source["efficiency"];
+	        this.cheated = source["cheated"];
+	        this.overshots = source["overshots"];
+	    }
+	}
 	export class MousePoint {
 	    // Go type: time
 	    ts: any;
@@ -308,11 +366,91 @@ export namespace models {
 	
 	
 	
+	export class WeaponSummary {
+	    weapon: string;
+	    shots: number;
+	    hits: number;
+	    damageDone: number;
+	    damagePossible: number;
+	    accuracy: number;
+	    efficiency: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new WeaponSummary(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.weapon = source["weapon"];
+	        this.shots = source["shots"];
+	        this.hits = source["hits"];
+	        this.damageDone = source["damageDone"];
+	        this.damagePossible = source["damagePossible"];
+	        this.accuracy = source["accuracy"];
+	        this.efficiency = source["efficiency"];
+	    }
+	}
+	export class ScenarioStats {
+	    score: number;
+	    hitCount: number;
+	    missCount: number;
+	    challengeStart: string;
+	    sensScale: string;
+	    horizSens: number;
+	    vertSens: number;
+	    dpi: number;
+	    fov: number;
+	    resolution: string;
+	    avgFps: number;
+	    pauseCount: number;
+	    pauseDuration: number;
+	    gameVersion: string;
+	    hash: string;
+	    accuracy: number;
+	    realAvgTtk: number;
+	    cm360: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ScenarioStats(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.score = source["score"];
+	        this.hitCount = source["hitCount"];
+	        this.missCount = source["missCount"];
+	        this.challengeStart = source["challengeStart"];
+	        this.sensScale = source["sensScale"];
+	        this.horizSens = source["horizSens"];
+	        this.vertSens = source["vertSens"];
+	        this.dpi = source["dpi"];
+	        this.fov = source["fov"];
+	        this.resolution = source["resolution"];
+	        this.avgFps = source["avgFps"];
+	        this.pauseCount = source["pauseCount"];
+	        this.pauseDuration = source["pauseDuration"];
+	        this.gameVersion = source["gameVersion"];
+	        this.hash = source["hash"];
+	        this.accuracy = source["accuracy"];
+	        this.realAvgTtk = source["realAvgTtk"];
+	        this.cm360 = source["cm360"];
+	    }
+	}
 	export class ScenarioRecord {
 	    filePath: string;
 	    fileName: string;
+	    mode: string;
+	    source?: string;
+	    contentHash?: string;
+	    playedAt: string;
 	    stats: Record<string, any>;
+	    scenarioStats: ScenarioStats;
 	    events: string[][];
+	    kills: KillEvent[];
+	    weapons: WeaponSummary[];
+	    killCount: number;
+	    startedAt?: string;
+	    hasTrace: boolean;
 	    mouseTrace?: MousePoint[];
 	
 	    static createFrom(source: any = {}) {
@@ -323,8 +461,18 @@ export namespace models {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.filePath = source["filePath"];
 	        this.fileName = source["fileName"];
+	        this.mode = source["mode"];
+	        this.source = source["source"];
+	        this.contentHash = source["contentHash"];
+	        this.playedAt = source["playedAt"];
 	        this.stats = source["stats"];
+	        this.scenarioStats = this.convertValues(source["scenarioStats"], ScenarioStats);
 	        this.events = source["events"];
+	        this.kills = this.convertValues(source["kills"], KillEvent);
+	        this.weapons = this.convertValues(source["weapons"], WeaponSummary);
+	        this.killCount = source["killCount"];
+	        this.startedAt = source["startedAt"];
+	        this.hasTrace = source["hasTrace"];
 	        this.mouseTrace = this.convertValues(source["mouseTrace"], MousePoint);
 	    }
 	
@@ -346,6 +494,180 @@ export namespace models {
 		    return a;
 		}
 	}
+	export class ScenarioSummary {
+	    filePath: string;
+	    fileName: string;
+	    scenarioName: string;
+	    mode: string;
+	    source?: string;
+	    datePlayed: string;
+	    playedAt: string;
+	    stats: Record<string, any>;
+	    scenarioStats: ScenarioStats;
+	    killCount: number;
+	    hasTrace: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new ScenarioSummary(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.filePath = source["filePath"];
+	        this.fileName = source["fileName"];
+	        this.scenarioName = source["scenarioName"];
+	        this.mode = source["mode"];
+	        this.source = source["source"];
+	        this.datePlayed = source["datePlayed"];
+	        this.playedAt = source["playedAt"];
+	        this.stats = source["stats"];
+	        this.scenarioStats = this.convertValues(source["scenarioStats"], ScenarioStats);
+	        this.killCount = source["killCount"];
+	        this.hasTrace = source["hasTrace"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ScenarioPage {
+	    total: number;
+	    offset: number;
+	    items: ScenarioSummary[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ScenarioPage(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.total = source["total"];
+	        this.offset = source["offset"];
+	        this.items = this.convertValues(source["items"], ScenarioSummary);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ScenarioQuery {
+	    name?: string;
+	    from?: string;
+	    to?: string;
+	    minCm360?: number;
+	    maxCm360?: number;
+	    mode?: string;
+	    sessionId?: string;
+	    tags?: string[];
+	    minScore?: number;
+	    sortBy?: string;
+	    ascending?: boolean;
+	    offset?: number;
+	    limit?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ScenarioQuery(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.from = source["from"];
+	        this.to = source["to"];
+	        this.minCm360 = source["minCm360"];
+	        this.maxCm360 = source["maxCm360"];
+	        this.mode = source["mode"];
+	        this.sessionId = source["sessionId"];
+	        this.tags = source["tags"];
+	        this.minScore = source["minScore"];
+	        this.sortBy = source["sortBy"];
+	        this.ascending = source["ascending"];
+	        this.offset = source["offset"];
+	        this.limit = source["limit"];
+	    }
+	}
+	export class Session {
+	    id: string;
+	    start: string;
+	    end: string;
+	    items: ScenarioSummary[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Session(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.start = source["start"];
+	        this.end = source["end"];
+	        this.items = this.convertValues(source["items"], ScenarioSummary);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class StatsSource {
+	    path: string;
+	    label?: string;
+	    recursive?: boolean;
+	    timezone?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new StatsSource(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.label = source["label"];
+	        this.recursive = source["recursive"];
+	        this.timezone = source["timezone"];
+	    }
+	}
 	export class Settings {
 	    steamInstallDir: string;
 	    steamIdOverride?: string;
@@ -357,6 +679,19 @@ export namespace models {
 	    mouseTrackingEnabled: boolean;
 	    mouseBufferMinutes: number;
 	    maxExistingOnStart: number;
+	    statsTimezone?: string;
+	    statsSources?: StatsSource[];
+	    scenarioTags?: Record<string, Array<string>>;
+	    apiEnabled: boolean;
+	    apiPort: number;
+	    apiToken?: string;
+	    overlayEnabled: boolean;
+	    overlayDir: string;
+	    overlayFormats?: Record<string, string>;
+	    traceMaxAgeDays: number;
+	    traceMaxTotalMB: number;
+	    traceKeepBest: number;
+	    traceDeleteOrphans: boolean;
 	
 	    static createFrom(source: any = {}) {
 	        return new Settings(source);
@@ -374,6 +709,143 @@ export namespace models {
 	        this.mouseTrackingEnabled = source["mouseTrackingEnabled"];
 	        this.mouseBufferMinutes = source["mouseBufferMinutes"];
 	        this.maxExistingOnStart = source["maxExistingOnStart"];
+	        this.statsTimezone = source["statsTimezone"];
+	        this.statsSources = this.convertValues(source["statsSources"], StatsSource);
+	        this.scenarioTags = source["scenarioTags"];
+	        this.apiEnabled = source["apiEnabled"];
+	        this.apiPort = source["apiPort"];
+	        this.apiToken = source["apiToken"];
+	        this.overlayEnabled = source["overlayEnabled"];
+	        this.overlayDir = source["overlayDir"];
+	        this.overlayFormats = source["overlayFormats"];
+	        this.traceMaxAgeDays = source["traceMaxAgeDays"];
+	        this.traceMaxTotalMB = source["traceMaxTotalMB"];
+	        this.traceKeepBest = source["traceKeepBest"];
+	        this.traceDeleteOrphans = source["traceDeleteOrphans"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class TraceGCReport {
+	    deleted: number;
+	    freedBytes: number;
+	    orphaned: number;
+	    expired: number;
+	    beyondBest: number;
+	    overBudget: number;
+	    corrupt: number;
+	    failed: number;
+	    remaining: number;
+	    remainingBytes: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new TraceGCReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.deleted = source["deleted"];
+	        this.freedBytes = source["freedBytes"];
+	        this.orphaned = source["orphaned"];
+	        this.expired = source["expired"];
+	        this.beyondBest = source["beyondBest"];
+	        this.overBudget = source["overBudget"];
+	        this.corrupt = source["corrupt"];
+	        this.failed = source["failed"];
+	        this.remaining = source["remaining"];
+	        this.remainingBytes = source["remainingBytes"];
+	    }
+	}
+	export class TraceInfo {
+	    fileName: string;
+	    traceFile: string;
+	    scenarioName?: string;
+	    datePlayed: string;
+	    points: number;
+	    durationMs: number;
+	    bytes: number;
+	    version: number;
+	    legacy?: boolean;
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new TraceInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.fileName = source["fileName"];
+	        this.traceFile = source["traceFile"];
+	        this.scenarioName = source["scenarioName"];
+	        this.datePlayed = source["datePlayed"];
+	        this.points = source["points"];
+	        this.durationMs = source["durationMs"];
+	        this.bytes = source["bytes"];
+	        this.version = source["version"];
+	        this.legacy = source["legacy"];
+	        this.error = source["error"];
+	    }
+	}
+	export class TraceMigrationReport {
+	    checked: number;
+	    upgraded: number;
+	    newer: number;
+	    failed: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new TraceMigrationReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.checked = source["checked"];
+	        this.upgraded = source["upgraded"];
+	        this.newer = source["newer"];
+	        this.failed = source["failed"];
+	    }
+	}
+	export class TraceStats {
+	    count: number;
+	    scenarios: number;
+	    totalBytes: number;
+	    totalPoints: number;
+	    totalDurationMs: number;
+	    legacy: number;
+	    unreadable: number;
+	    oldest?: string;
+	    newest?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new TraceStats(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.count = source["count"];
+	        this.scenarios = source["scenarios"];
+	        this.totalBytes = source["totalBytes"];
+	        this.totalPoints = source["totalPoints"];
+	        this.totalDurationMs = source["totalDurationMs"];
+	        this.legacy = source["legacy"];
+	        this.unreadable = source["unreadable"];
+	        this.oldest = source["oldest"];
+	        this.newest = source["newest"];
 	    }
 	}
 	export class UpdateInfo {
@@ -0,0 +1,276 @@
+// Package api serves the scenario, session and benchmark data of RefleK's
+// over a token-protected HTTP/JSON API on localhost, plus a server-sent event
+// stream of the watcher events.
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"refleks/internal/benchmarks"
+	"refleks/internal/constants"
+	"refleks/internal/models"
+)
+
+// Backend supplies the data served by the API.
+type Backend interface {
+	GetRecent(limit int) []models.ScenarioSummary
+	GetScenarioDetail(filePath string) (models.ScenarioRecord, error)
+	QueryScenarios(q models.ScenarioQuery) (models.ScenarioPage, error)
+	GetSessions(from, to string) ([]models.Session, error)
+}
+
+// Server is the local HTTP API. It is also an events.EventSink: emitted events
+// are forwarded to the connected /events streams.
+type Server struct {
+	backend Backend
+
+	mu    sync.Mutex
+	srv   *http.Server
+	addr  string
+	token string
+
+	hub hub
+}
+
+// New returns a stopped server serving data from backend.
+func New(backend Backend) *Server {
+	return &Server{backend: backend, hub: hub{subs: make(map[*subscriber]struct{})}}
+}
+
+// Start listens on 127.0.0.1:port and requires token on every request. A
+// running server is restarted when the port or token changed.
+func (s *Server) Start(port int, token string) error {
+	if strings.TrimSpace(token) == "" {
+		return errors.New("api token is empty")
+	}
+	addr := net.JoinHostPort("127.0.0.1", strconv.Itoa(port))
+	s.mu.Lock()
+	if s.srv != nil && s.addr == addr && s.token == token {
+		s.mu.Unlock()
+		return nil
+	}
+	s.mu.Unlock()
+	_ = s.Stop()
+
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("api listen %s: %w", addr, err)
+	}
+	srv := &http.Server{Handler: s.handler(token), ReadHeaderTimeout: 10 * time.Second}
+	s.mu.Lock()
+	s.srv, s.addr, s.token = srv, addr, token
+	s.mu.Unlock()
+	go func() { _ = srv.Serve(ln) }()
+	return nil
+}
+
+// Stop shuts the server down and closes the event streams.
+func (s *Server) Stop() error {
+	s.mu.Lock()
+	srv := s.srv
+	s.srv, s.addr, s.token = nil, "", ""
+	s.mu.Unlock()
+	if srv == nil {
+		return nil
+	}
+	s.hub.closeAll()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return srv.Shutdown(ctx)
+}
+
+// Addr returns the listen address, or "" when stopped.
+func (s *Server) Addr() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addr
+}
+
+func (s *Server) handler(token string) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/health", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"version": constants.AppVersion})
+	})
+	mux.HandleFunc("GET /api/v1/scenarios/recent", s.handleRecent)
+	mux.HandleFunc("GET /api/v1/scenarios", s.handleQuery)
+	mux.HandleFunc("GET /api/v1/scenario", s.handleDetail)
+	mux.HandleFunc("GET /api/v1/sessions", s.handleSessions)
+	mux.HandleFunc("GET /api/v1/benchmarks/{id}/progress", s.handleBenchmarkProgress)
+	mux.HandleFunc("GET "+eventsPath, s.handleEvents)
+	return withAuth(token, mux)
+}
+
+// eventsPath is the only endpoint accepting the token as a query parameter,
+// since EventSource cannot set headers.
+const eventsPath = "/api/v1/events"
+
+// withAuth rejects requests whose Host is not the loopback address (DNS
+// rebinding) and requires the token as a bearer token, or as ?token= on the
+// event stream. No CORS headers are sent, so browsers cannot read responses
+// from other origins.
+func withAuth(token string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !isLoopbackHost(r.Host) {
+			writeError(w, http.StatusForbidden, fmt.Errorf("host %q not allowed", r.Host))
+			return
+		}
+		got, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if got == "" && r.URL.Path == eventsPath {
+			got = r.URL.Query().Get("token")
+		}
+		if token == "" || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			writeError(w, http.StatusUnauthorized, errors.New("missing or invalid token"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// isLoopbackHost reports whether a Host header names this machine.
+func isLoopbackHost(host string) bool {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.TrimSuffix(strings.TrimPrefix(host, "["), "]")
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+func (s *Server) handleRecent(w http.ResponseWriter, r *http.Request) {
+	limit, err := intParam(r, "limit")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	items := s.backend.GetRecent(limit)
+	if items == nil {
+		items = []models.ScenarioSummary{}
+	}
+	writeJSON(w, http.StatusOK, items)
+}
+
+func (s *Server) handleQuery(w http.ResponseWriter, r *http.Request) {
+	q, err := parseQuery(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	page, err := s.backend.QueryScenarios(q)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, page)
+}
+
+func (s *Server) handleDetail(w http.ResponseWriter, r *http.Request) {
+	path := r.URL.Query().Get("path")
+	if path == "" {
+		writeError(w, http.StatusBadRequest, errors.New("missing path"))
+		return
+	}
+	rec, err := s.backend.GetScenarioDetail(path)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, rec)
+}
+
+func (s *Server) handleSessions(w http.ResponseWriter, r *http.Request) {
+	list, err := s.backend.GetSessions(r.URL.Query().Get("from"), r.URL.Query().Get("to"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if list == nil {
+		list = []models.Session{}
+	}
+	writeJSON(w, http.StatusOK, list)
+}
+
+func (s *Server) handleBenchmarkProgress(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid benchmark id %q", r.PathValue("id")))
+		return
+	}
+	prog, err := benchmarks.GetBenchmarkProgress(id)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, prog)
+}
+
+// parseQuery maps URL parameters onto a ScenarioQuery. Tags may repeat.
+func parseQuery(r *http.Request) (models.ScenarioQuery, error) {
+	v := r.URL.Query()
+	q := models.ScenarioQuery{
+		Name:      v.Get("name"),
+		From:      v.Get("from"),
+		To:        v.Get("to"),
+		Mode:      v.Get("mode"),
+		SessionID: v.Get("sessionId"),
+		Tags:      v["tag"],
+		SortBy:    v.Get("sort"),
+		Ascending: v.Get("asc") == "true" || v.Get("asc") == "1",
+	}
+	var err error
+	if q.Offset, err = intParam(r, "offset"); err != nil {
+		return q, err
+	}
+	if q.Limit, err = intParam(r, "limit"); err != nil {
+		return q, err
+	}
+	for name, dst := range map[string]*float64{"minCm360": &q.MinCm360, "maxCm360": &q.MaxCm360} {
+		if s := v.Get(name); s != "" {
+			if *dst, err = strconv.ParseFloat(s, 64); err != nil {
+				return q, fmt.Errorf("invalid %s: %w", name, err)
+			}
+		}
+	}
+	if s := v.Get("minScore"); s != "" {
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return q, fmt.Errorf("invalid minScore: %w", err)
+		}
+		q.MinScore = &f
+	}
+	return q, nil
+}
+
+func intParam(r *http.Request, name string) (int, error) {
+	s := r.URL.Query().Get(name)
+	if s == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", name, err)
+	}
+	return n, nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
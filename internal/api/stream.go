@@ -0,0 +1,134 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"refleks/internal/constants"
+	"refleks/internal/events"
+)
+
+// event is one emitted event, encoded once for all subscribers.
+type event struct {
+	name string
+	data []byte
+}
+
+// subscriber is one connected event stream. A nil names set receives every event.
+type subscriber struct {
+	names map[string]bool
+	ch    chan event
+}
+
+// hub fans out emitted events to the connected streams.
+type hub struct {
+	mu   sync.Mutex
+	subs map[*subscriber]struct{}
+}
+
+func (h *hub) add(sub *subscriber) {
+	h.mu.Lock()
+	h.subs[sub] = struct{}{}
+	h.mu.Unlock()
+}
+
+func (h *hub) remove(sub *subscriber) {
+	h.mu.Lock()
+	if _, ok := h.subs[sub]; ok {
+		delete(h.subs, sub)
+		close(sub.ch)
+	}
+	h.mu.Unlock()
+}
+
+func (h *hub) closeAll() {
+	h.mu.Lock()
+	for sub := range h.subs {
+		delete(h.subs, sub)
+		close(sub.ch)
+	}
+	h.mu.Unlock()
+}
+
+func (h *hub) publish(e event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for sub := range h.subs {
+		if sub.names != nil && !sub.names[e.name] {
+			continue
+		}
+		select {
+		case sub.ch <- e:
+		default:
+			// Slow client; drop rather than block the watcher.
+		}
+	}
+}
+
+// Emit forwards an event to the connected streams.
+func (s *Server) Emit(name string, data any) {
+	s.hub.mu.Lock()
+	idle := len(s.hub.subs) == 0
+	s.hub.mu.Unlock()
+	if idle {
+		return
+	}
+	b, err := json.Marshal(data)
+	if err != nil {
+		return
+	}
+	s.hub.publish(event{name: name, data: b})
+}
+
+// Logf ignores log messages; only events are streamed.
+func (s *Server) Logf(events.Level, string, ...any) {}
+
+// handleEvents streams events as server-sent events. ?events=A,B limits the
+// stream to the named events (e.g. ScenarioAdded).
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("streaming unsupported"))
+		return
+	}
+	sub := &subscriber{ch: make(chan event, constants.APIEventBuffer)}
+	if list := r.URL.Query().Get("events"); list != "" {
+		sub.names = make(map[string]bool)
+		for _, n := range strings.Split(list, ",") {
+			if n = strings.TrimSpace(n); n != "" {
+				sub.names[n] = true
+			}
+		}
+	}
+	s.hub.add(sub)
+	defer s.hub.remove(sub)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, ": connected\n\n")
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(constants.APIKeepAliveSeconds * time.Second)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case e, ok := <-sub.ch:
+			if !ok {
+				return
+			}
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.name, e.data)
+			flusher.Flush()
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": ping\n\n")
+			flusher.Flush()
+		}
+	}
+}
@@ -2,13 +2,16 @@ package appsvc
 
 import (
 	"context"
+	"errors"
+	"sync"
 	"time"
 
-	"github.com/wailsapp/wails/v2/pkg/runtime"
-
+	"refleks/internal/api"
 	"refleks/internal/constants"
+	"refleks/internal/events"
 	"refleks/internal/models"
 	"refleks/internal/mouse"
+	"refleks/internal/overlay"
 	appsettings "refleks/internal/settings"
 	"refleks/internal/traces"
 )
@@ -16,34 +19,77 @@ import (
 // AppService coordinates mouse, watcher and updater services and centralizes
 // settings-related side effects so `app.go` remains small and focused on IPC.
 type AppService struct {
-	ctx      context.Context
+	sink     events.EventSink
+	api      *api.Server
+	overlay  *overlay.Writer
 	watcher  *WatcherService
 	updater  *UpdaterService
 	mouse    mouse.Provider
 	settings *models.Settings
+	// traceGC ensures the startup trace cleanup runs once.
+	traceGC sync.Once
 }
 
-// NewAppService constructs and wires the subservices.
-func NewAppService(ctx context.Context, settings *models.Settings) *AppService {
-	svc := &AppService{ctx: ctx, settings: settings}
+// NewAppService constructs and wires the subservices. Events and log messages
+// of the app and its watcher are published to sink and, when enabled, to the
+// local HTTP API's event stream.
+func NewAppService(sink events.EventSink, settings *models.Settings) *AppService {
+	svc := &AppService{settings: settings}
+	svc.api = api.New(svc)
+	svc.overlay = overlay.New(svc, sink)
+	svc.sink = events.Multi(sink, svc.api, svc.overlay)
 	// Mouse provider initialization (platform-specific noop on non-Windows)
 	svc.mouse = mouse.New(constants.DefaultMouseSampleHz)
 	if settings != nil {
 		svc.mouse.SetBufferDuration(time.Duration(settings.MouseBufferMinutes) * time.Minute)
 		if settings.MouseTrackingEnabled {
 			if err := svc.mouse.Start(); err != nil {
-				runtime.LogWarningf(ctx, "mouse tracker start failed: %v", err)
+				sink.Logf(events.Warning, "mouse tracker start failed: %v", err)
 			} else {
-				runtime.LogInfo(ctx, "mouse tracker started")
+				sink.Logf(events.Info, "mouse tracker started")
 			}
 		}
 	}
-	svc.watcher = NewWatcherService(ctx)
+	traces.SetRecoveryHandler(func(fileName, backup string) {
+		svc.sink.Logf(events.Warning, "mouse trace of %s was damaged; recovered from %s", fileName, backup)
+	})
+	svc.watcher = NewWatcherService(svc.sink)
 	svc.watcher.SetMouseProvider(svc.mouse)
 	svc.updater = NewUpdaterService(constants.GitHubOwner, constants.GitHubRepo, constants.AppVersion)
+	svc.applyAPI()
+	if settings != nil {
+		if err := svc.applyOverlay(*settings); err != nil {
+			sink.Logf(events.Warning, "stream overlay disabled: %v", err)
+		}
+	}
 	return svc
 }
 
+// applyOverlay configures the stream overlay files from settings.
+func (s *AppService) applyOverlay(st models.Settings) error {
+	return s.overlay.Configure(st.OverlayEnabled, appsettings.ExpandPathPlaceholders(st.OverlayDir), st.OverlayFormats)
+}
+
+// applyAPI starts, restarts or stops the local HTTP API to match the settings.
+func (s *AppService) applyAPI() {
+	if s.settings == nil || !s.settings.APIEnabled {
+		_ = s.api.Stop()
+		return
+	}
+	if s.settings.APIToken == "" {
+		s.settings.APIToken = appsettings.NewAPIToken()
+		_ = appsettings.Save(*s.settings)
+	}
+	prev := s.api.Addr()
+	if err := s.api.Start(s.settings.APIPort, s.settings.APIToken); err != nil {
+		s.sink.Logf(events.Error, "local API start failed: %v", err)
+		return
+	}
+	if addr := s.api.Addr(); addr != prev {
+		s.sink.Logf(events.Info, "local API listening on http://%s", addr)
+	}
+}
+
 // CheckForUpdates delegates to the updater service.
 func (s *AppService) CheckForUpdates(ctx context.Context) (models.UpdateInfo, error) {
 	return s.updater.CheckForUpdates(ctx)
@@ -59,7 +105,14 @@ func (s *AppService) StartWatcher(path string) (bool, string) {
 	if s.settings == nil {
 		return false, "missing settings"
 	}
-	return s.watcher.Start(path, s.settings, s.mouse)
+	ok, msg := s.watcher.Start(path, s.settings, s.mouse)
+	if ok {
+		// Clean up traces once the initial runs are loaded, so best-N can rank them.
+		s.traceGC.Do(func() {
+			go func() { _, _ = s.CollectTraceGarbage() }()
+		})
+	}
+	return ok, msg
 }
 
 // StopWatcher stops the watcher.
@@ -67,11 +120,85 @@ func (s *AppService) StopWatcher() (bool, string) {
 	return s.watcher.Stop()
 }
 
-// GetRecent returns recent scenarios.
-func (s *AppService) GetRecent(limit int) []models.ScenarioRecord {
+// GetRecent returns recent scenario summaries.
+func (s *AppService) GetRecent(limit int) []models.ScenarioSummary {
 	return s.watcher.GetRecent(limit)
 }
 
+// GetScenarioDetail returns the full record of one scenario run.
+func (s *AppService) GetScenarioDetail(filePath string) (models.ScenarioRecord, error) {
+	return s.watcher.GetDetail(filePath)
+}
+
+// ImportStatsArchive imports stats files from an archive of an old stats folder
+// into the given stats source (path or label; empty for the primary one).
+func (s *AppService) ImportStatsArchive(path, source string) (models.ImportProgress, error) {
+	return s.watcher.ImportArchive(path, source)
+}
+
+// QueryScenarios runs a scenario query using the user's scenario tags.
+func (s *AppService) QueryScenarios(q models.ScenarioQuery) (models.ScenarioPage, error) {
+	var tags map[string][]string
+	if s.settings != nil {
+		tags = s.settings.ScenarioTags
+	}
+	return s.watcher.QueryScenarios(q, tags)
+}
+
+// GetSessions returns the sessions overlapping the given RFC3339 range.
+func (s *AppService) GetSessions(from, to string) ([]models.Session, error) {
+	return s.watcher.GetSessions(from, to)
+}
+
+// CollectTraceGarbage applies the trace retention settings to the traces
+// directory and reports what was deleted. Rules that need the list of stats
+// files are skipped when it cannot be built.
+func (s *AppService) CollectTraceGarbage() (models.TraceGCReport, error) {
+	if s.settings == nil {
+		return models.TraceGCReport{}, errors.New("missing settings")
+	}
+	p := tracePolicy(*s.settings)
+	var runs []traces.Run
+	if p.DeleteOrphans || p.KeepBestPerScenario > 0 {
+		var err error
+		if runs, err = s.watcher.TraceRuns(); err != nil {
+			s.sink.Logf(events.Warning, "trace cleanup: skipping orphan and best-run rules: %v", err)
+		}
+	}
+	rep, err := traces.GC(p, runs, time.Now())
+	if err != nil {
+		return rep, err
+	}
+	if rep.Deleted > 0 {
+		s.watcher.ReloadTraces()
+		s.sink.Logf(events.Info, "trace cleanup freed %.1f MB (%d traces: %d orphaned, %d expired, %d beyond best, %d over size limit, %d corrupt)",
+			float64(rep.FreedBytes)/(1<<20), rep.Deleted, rep.Orphaned, rep.Expired, rep.BeyondBest, rep.OverBudget, rep.Corrupt)
+	}
+	if rep.Failed > 0 {
+		s.sink.Logf(events.Warning, "trace cleanup could not delete %d traces", rep.Failed)
+	}
+	return rep, nil
+}
+
+// DeleteTrace deletes the stored trace of a run and clears its HasTrace flag.
+func (s *AppService) DeleteTrace(fileName string) error {
+	if err := traces.Delete(fileName); err != nil {
+		return err
+	}
+	s.watcher.ReloadTraces()
+	return nil
+}
+
+// tracePolicy converts the trace retention settings.
+func tracePolicy(st models.Settings) traces.Policy {
+	return traces.Policy{
+		MaxAge:              time.Duration(st.TraceMaxAgeDays) * 24 * time.Hour,
+		MaxTotalBytes:       int64(st.TraceMaxTotalMB) << 20,
+		KeepBestPerScenario: st.TraceKeepBest,
+		DeleteOrphans:       st.TraceDeleteOrphans,
+	}
+}
+
 // IsWatcherRunning indicates if the watcher loop is active.
 func (s *AppService) IsWatcherRunning() bool {
 	return s.watcher.IsRunning()
@@ -81,6 +208,12 @@ func (s *AppService) IsWatcherRunning() bool {
 // sub-services (mouse, watcher, traces) to reflect the change.
 func (s *AppService) UpdateSettings(newS models.Settings) (bool, string) {
 	newS = appsettings.Sanitize(newS)
+	if err := appsettings.ValidateTimezones(newS); err != nil {
+		return false, err.Error()
+	}
+	if _, err := overlay.Compile(newS.OverlayFormats); err != nil {
+		return false, err.Error()
+	}
 	prevTraces := ""
 	if s.settings != nil {
 		prevTraces = s.settings.TracesDir
@@ -88,6 +221,16 @@ func (s *AppService) UpdateSettings(newS models.Settings) (bool, string) {
 		if len(newS.FavoriteBenchmarks) == 0 && len(s.settings.FavoriteBenchmarks) > 0 {
 			newS.FavoriteBenchmarks = s.settings.FavoriteBenchmarks
 		}
+		// carry over extra stats sources and tags if omitted (an explicit empty value clears them)
+		if newS.StatsSources == nil {
+			newS.StatsSources = s.settings.StatsSources
+		}
+		if newS.ScenarioTags == nil {
+			newS.ScenarioTags = s.settings.ScenarioTags
+		}
+		if newS.OverlayFormats == nil {
+			newS.OverlayFormats = s.settings.OverlayFormats
+		}
 	}
 	// replace in-place so callers holding the pointer observe the change
 	if s.settings != nil {
@@ -104,7 +247,7 @@ func (s *AppService) UpdateSettings(newS models.Settings) (bool, string) {
 	if newS.MouseTrackingEnabled {
 		if !s.mouse.Enabled() {
 			if err := s.mouse.Start(); err != nil {
-				runtime.LogWarningf(s.ctx, "mouse tracker start failed: %v", err)
+				s.sink.Logf(events.Warning, "mouse tracker start failed: %v", err)
 			}
 		}
 	} else {
@@ -115,13 +258,7 @@ func (s *AppService) UpdateSettings(newS models.Settings) (bool, string) {
 
 	// Ensure watcher reflects latest settings. If running, restart with new config; if stopped, just update config.
 	if s.watcher != nil {
-		cfg := models.WatcherConfig{
-			Path:                 newS.StatsDir,
-			SessionGap:           time.Duration(newS.SessionGapMinutes) * time.Minute,
-			PollInterval:         time.Duration(constants.DefaultPollIntervalSeconds) * time.Second,
-			ParseExistingOnStart: true,
-			ParseExistingLimit:   newS.MaxExistingOnStart,
-		}
+		cfg := appsettings.WatcherConfig(newS.StatsDir, newS)
 		if s.watcher.IsRunning() {
 			_, _ = s.watcher.Stop()
 			if err := s.watcher.UpdateConfig(cfg); err != nil {
@@ -132,7 +269,7 @@ func (s *AppService) UpdateSettings(newS models.Settings) (bool, string) {
 				s.watcher.SetMouseProvider(s.mouse)
 			}
 			if ok, msg := s.watcher.Start(newS.StatsDir, s.settings, s.mouse); !ok {
-				runtime.LogErrorf(s.ctx, "Watcher restart error: %s", msg)
+				s.sink.Logf(events.Error, "Watcher restart error: %s", msg)
 				return false, msg
 			}
 		} else {
@@ -151,7 +288,11 @@ func (s *AppService) UpdateSettings(newS models.Settings) (bool, string) {
 	traces.SetBaseDir(tracesDir)
 	if s.watcher != nil && appsettings.ExpandPathPlaceholders(prevTraces) != tracesDir {
 		n := s.watcher.ReloadTraces()
-		runtime.LogInfof(s.ctx, "reloaded traces for %d scenarios after tracesDir change", n)
+		s.sink.Logf(events.Info, "reloaded traces for %d scenarios after tracesDir change", n)
+	}
+	s.applyAPI()
+	if err := s.applyOverlay(newS); err != nil {
+		return false, err.Error()
 	}
 	return true, "ok"
 }
@@ -1,27 +1,28 @@
 package appsvc
 
 import (
-	"context"
+	"errors"
+	"fmt"
+	"strings"
 	"time"
 
-	"github.com/wailsapp/wails/v2/pkg/runtime"
-
-	"refleks/internal/constants"
+	"refleks/internal/events"
 	"refleks/internal/models"
 	"refleks/internal/mouse"
 	"refleks/internal/settings"
+	"refleks/internal/traces"
 	"refleks/internal/watcher"
 )
 
 // WatcherService wraps the watcher.Watcher and provides a smaller surface for app.go.
 type WatcherService struct {
-	ctx context.Context
-	w   *watcher.Watcher
+	sink events.EventSink
+	w    *watcher.Watcher
 }
 
-// NewWatcherService creates a new service bound to the provided context.
-func NewWatcherService(ctx context.Context) *WatcherService {
-	return &WatcherService{ctx: ctx}
+// NewWatcherService creates a new service publishing to the given sink.
+func NewWatcherService(sink events.EventSink) *WatcherService {
+	return &WatcherService{sink: sink}
 }
 
 // Start begins monitoring the given path. If path is empty, the settings' StatsDir or default is used.
@@ -40,15 +41,9 @@ func (s *WatcherService) Start(path string, cfgSettings *models.Settings, mouseP
 		cfgSettings.StatsDir = path
 		_ = settings.Save(*cfgSettings)
 	}
-	cfg := models.WatcherConfig{
-		Path:                 path,
-		SessionGap:           time.Duration(cfgSettings.SessionGapMinutes) * time.Minute,
-		PollInterval:         time.Duration(constants.DefaultPollIntervalSeconds) * time.Second,
-		ParseExistingOnStart: true,
-		ParseExistingLimit:   cfgSettings.MaxExistingOnStart,
-	}
+	cfg := settings.WatcherConfig(path, *cfgSettings)
 	if s.w == nil {
-		s.w = watcher.New(s.ctx, cfg)
+		s.w = watcher.New(s.sink, cfg)
 		if mouseProv != nil {
 			s.w.SetMouseProvider(mouseProv)
 		}
@@ -59,7 +54,7 @@ func (s *WatcherService) Start(path string, cfgSettings *models.Settings, mouseP
 		s.w.Clear()
 	}
 	if err := s.w.Start(); err != nil {
-		runtime.LogErrorf(s.ctx, "Watcher start error: %v", err)
+		s.sink.Logf(events.Error, "Watcher start error: %v", err)
 		return false, err.Error()
 	}
 	return true, "ok"
@@ -76,14 +71,55 @@ func (s *WatcherService) Stop() (bool, string) {
 	return true, "stopped"
 }
 
-// GetRecent returns most recent parsed scenarios, up to optional limit.
-func (s *WatcherService) GetRecent(limit int) []models.ScenarioRecord {
+// GetRecent returns summaries of the most recent parsed scenarios, up to optional limit.
+func (s *WatcherService) GetRecent(limit int) []models.ScenarioSummary {
 	if s.w == nil {
 		return nil
 	}
 	return s.w.GetRecent(limit)
 }
 
+// GetDetail returns the full record of one scenario run.
+func (s *WatcherService) GetDetail(filePath string) (models.ScenarioRecord, error) {
+	if s.w == nil {
+		return models.ScenarioRecord{}, fmt.Errorf("watcher not started")
+	}
+	return s.w.GetDetail(filePath)
+}
+
+// GetSessions returns the sessions overlapping [from, to], most-recent-first.
+// Bounds are RFC3339 timestamps; an empty bound leaves that side open.
+func (s *WatcherService) GetSessions(from, to string) ([]models.Session, error) {
+	if s.w == nil {
+		return nil, nil
+	}
+	start, err := parseBound(from)
+	if err != nil {
+		return nil, fmt.Errorf("invalid from: %w", err)
+	}
+	end, err := parseBound(to)
+	if err != nil {
+		return nil, fmt.Errorf("invalid to: %w", err)
+	}
+	return s.w.GetSessions(start, end), nil
+}
+
+// QueryScenarios filters, sorts and pages the known scenario runs.
+func (s *WatcherService) QueryScenarios(q models.ScenarioQuery, tags map[string][]string) (models.ScenarioPage, error) {
+	if s.w == nil {
+		return models.ScenarioPage{Items: []models.ScenarioSummary{}}, nil
+	}
+	return s.w.QueryScenarios(q, tags)
+}
+
+// parseBound parses an optional RFC3339 range bound.
+func parseBound(v string) (time.Time, error) {
+	if strings.TrimSpace(v) == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, strings.TrimSpace(v))
+}
+
 // IsRunning indicates if the watcher loop is active.
 func (s *WatcherService) IsRunning() bool {
 	if s.w == nil {
@@ -95,7 +131,7 @@ func (s *WatcherService) IsRunning() bool {
 // UpdateConfig updates the watcher's configuration while stopped (or creates the watcher if nil).
 func (s *WatcherService) UpdateConfig(cfg models.WatcherConfig) error {
 	if s.w == nil {
-		s.w = watcher.New(s.ctx, cfg)
+		s.w = watcher.New(s.sink, cfg)
 		return nil
 	}
 	return s.w.UpdateConfig(cfg)
@@ -117,6 +153,14 @@ func (s *WatcherService) SetMouseProvider(p mouse.Provider) {
 	s.w.SetMouseProvider(p)
 }
 
+// ImportArchive imports stats files from a .zip or .tar(.gz) archive.
+func (s *WatcherService) ImportArchive(path, source string) (models.ImportProgress, error) {
+	if s.w == nil {
+		return models.ImportProgress{Archive: path}, errors.New("watcher not initialized")
+	}
+	return s.w.ImportArchive(path, source)
+}
+
 // ReloadTraces attempts to load persisted traces; returns number reloaded.
 func (s *WatcherService) ReloadTraces() int {
 	if s.w == nil {
@@ -124,3 +168,11 @@ func (s *WatcherService) ReloadTraces() int {
 	}
 	return s.w.ReloadTraces()
 }
+
+// TraceRuns lists the runs persisted traces may belong to.
+func (s *WatcherService) TraceRuns() ([]traces.Run, error) {
+	if s.w == nil {
+		return nil, errors.New("watcher not initialized")
+	}
+	return s.w.TraceRuns()
+}
@@ -0,0 +1,151 @@
+// Package atomicfile writes files so that a crash or power loss never leaves
+// them truncated, keeping rotating backups that reads fall back to.
+//
+// Write stores data in a temporary file next to the target, fsyncs it and
+// renames it over the target, which therefore always exists. The previous
+// file is kept as "<path>.bak.1", older ones shift to ".bak.2" and so on.
+package atomicfile
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// backupPath returns the name of the n-th (1 = newest) backup of path.
+func backupPath(path string, n int) string {
+	return fmt.Sprintf("%s.bak.%d", path, n)
+}
+
+// Write atomically replaces path with data, keeping up to backups previous
+// versions. With backups == 0 no copies are kept.
+func Write(path string, data []byte, perm os.FileMode, backups int) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(dir, filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+	_, err = f.Write(data)
+	if err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Chmod(tmp, perm)
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+
+	if backups > 0 {
+		if _, err := os.Stat(path); err == nil {
+			_ = os.Remove(backupPath(path, backups))
+			for n := backups - 1; n >= 1; n-- {
+				_ = os.Rename(backupPath(path, n), backupPath(path, n+1))
+			}
+			// Link (or copy) rather than rename, so path keeps existing
+			// until tmp replaces it.
+			if err := linkOrCopy(path, backupPath(path, 1)); err != nil {
+				_ = os.Remove(tmp)
+				return err
+			}
+		}
+	}
+	beforeReplace(path)
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	syncDir(dir)
+	return nil
+}
+
+// beforeReplace runs right before the new data is renamed into place; tests
+// use it to observe the state a crash at that point would leave.
+var beforeReplace = func(path string) {}
+
+// linkOrCopy makes dst a copy of src, as a hard link where the file system
+// supports one.
+func linkOrCopy(src, dst string) error {
+	if err := os.Link(src, dst); err == nil {
+		return nil
+	}
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	fi, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(dst, data, fi.Mode().Perm())
+}
+
+// syncDir flushes the directory entry of a rename. Not every platform can
+// open directories (e.g. Windows), so failures are ignored.
+func syncDir(dir string) {
+	if d, err := os.Open(dir); err == nil {
+		_ = d.Sync()
+		_ = d.Close()
+	}
+}
+
+// ErrUnsupported marks data that is intact but written by a newer version of
+// the app. valid should wrap it so Read returns the error as is, without
+// falling back to a backup or quarantining the file.
+var ErrUnsupported = errors.New("unsupported data version")
+
+// CorruptSuffix is appended to a damaged file that Read replaced by a backup.
+const CorruptSuffix = ".corrupt"
+
+// Read returns the contents of path. When the file is missing or valid
+// rejects it, the newest backup that valid accepts is returned instead along
+// with its name in recoveredFrom; a damaged file is kept as "<path>.corrupt"
+// so the next Write does not rotate it into the backups. If nothing usable is
+// found the error of the primary file is returned.
+func Read(path string, valid func([]byte) error) (data []byte, recoveredFrom string, err error) {
+	data, err = os.ReadFile(path)
+	if err == nil {
+		if err = valid(data); err == nil {
+			return data, "", nil
+		}
+		if errors.Is(err, ErrUnsupported) {
+			return nil, "", err
+		}
+	}
+	for n := 1; ; n++ {
+		bak := backupPath(path, n)
+		b, berr := os.ReadFile(bak)
+		if errors.Is(berr, os.ErrNotExist) {
+			break
+		}
+		if berr != nil || valid(b) != nil {
+			continue
+		}
+		if _, serr := os.Stat(path); serr == nil {
+			_ = os.Rename(path, path+CorruptSuffix)
+		}
+		return b, bak, nil
+	}
+	return nil, "", err
+}
+
+// Remove deletes path and its backups. It returns the error of removing path
+// itself, so a missing file reports os.ErrNotExist.
+func Remove(path string) error {
+	err := os.Remove(path)
+	for n := 1; ; n++ {
+		if rerr := os.Remove(backupPath(path, n)); errors.Is(rerr, os.ErrNotExist) {
+			break
+		}
+	}
+	return err
+}
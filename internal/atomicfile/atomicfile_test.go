@@ -0,0 +1,161 @@
+package atomicfile
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// validOK accepts data starting with "ok" and marks "new" data as unsupported.
+func validOK(b []byte) error {
+	switch {
+	case strings.HasPrefix(string(b), "ok"):
+		return nil
+	case strings.HasPrefix(string(b), "new"):
+		return fmt.Errorf("newer data: %w", ErrUnsupported)
+	}
+	return errors.New("corrupt")
+}
+
+// writeAll writes each version of path in turn, keeping backups copies.
+func writeAll(t *testing.T, path string, backups int, versions ...string) {
+	t.Helper()
+	for _, v := range versions {
+		if err := Write(path, []byte(v), 0o644, backups); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestWriteRotatesBackups(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "settings.json")
+	writeAll(t, path, 2, "ok1", "ok2", "ok3", "ok4")
+	for name, want := range map[string]string{path: "ok4", backupPath(path, 1): "ok3", backupPath(path, 2): "ok2"} {
+		if b, err := os.ReadFile(name); err != nil || string(b) != want {
+			t.Fatalf("%s = %q, %v; want %q", name, b, err, want)
+		}
+	}
+	if _, err := os.Stat(backupPath(path, 3)); !os.IsNotExist(err) {
+		t.Fatalf("third backup kept: %v", err)
+	}
+}
+
+func TestWriteNeverRemovesTarget(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "settings.json")
+	writeAll(t, path, 3, "ok0")
+
+	// Check what a crash just before the final rename would leave behind.
+	var checked int
+	beforeReplace = func(p string) {
+		checked++
+		if _, err := os.Stat(p); err != nil {
+			t.Errorf("write %d: target missing before replace: %v", checked, err)
+		}
+		if _, err := os.Stat(backupPath(p, 1)); err != nil {
+			t.Errorf("write %d: backup missing before replace: %v", checked, err)
+		}
+	}
+	t.Cleanup(func() { beforeReplace = func(string) {} })
+
+	for i := 1; i <= 5; i++ {
+		if err := Write(path, []byte(fmt.Sprintf("ok%d", i)), 0o644, 3); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if checked != 5 {
+		t.Fatalf("hook ran %d times, want 5", checked)
+	}
+	if b, err := os.ReadFile(backupPath(path, 1)); err != nil || string(b) != "ok4" {
+		t.Fatalf("newest backup = %q, %v; want ok4", b, err)
+	}
+	if b, err := os.ReadFile(path); err != nil || string(b) != "ok5" {
+		t.Fatalf("target = %q, %v; want ok5", b, err)
+	}
+}
+
+func TestReadRecoversFromBackup(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "settings.json")
+	writeAll(t, path, 2, "ok1", "ok2", "ok3")
+	if err := os.WriteFile(path, []byte("garbage"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// A damaged newest backup is skipped in favour of the next one.
+	if err := os.WriteFile(backupPath(path, 1), []byte{0}, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	data, from, err := Read(path, validOK)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "ok1" || from != backupPath(path, 2) {
+		t.Fatalf("Read = %q from %q, want ok1 from %q", data, from, backupPath(path, 2))
+	}
+	if b, err := os.ReadFile(path + CorruptSuffix); err != nil || string(b) != "garbage" {
+		t.Fatalf("damaged file not quarantined: %q, %v", b, err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("damaged file left in place: %v", err)
+	}
+}
+
+func TestReadMissingPrimary(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "trace.rtrace")
+	writeAll(t, path, 1, "ok1", "ok2")
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	data, from, err := Read(path, validOK)
+	if err != nil || string(data) != "ok1" || from != backupPath(path, 1) {
+		t.Fatalf("Read = %q from %q, %v; want ok1 from the backup", data, from, err)
+	}
+}
+
+func TestReadUnsupportedKeepsFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "trace.rtrace")
+	writeAll(t, path, 1, "ok1", "new2")
+
+	_, _, err := Read(path, validOK)
+	if !errors.Is(err, ErrUnsupported) {
+		t.Fatalf("err = %v, want ErrUnsupported", err)
+	}
+	if b, err := os.ReadFile(path); err != nil || string(b) != "new2" {
+		t.Fatalf("newer file was moved: %q, %v", b, err)
+	}
+	if _, err := os.Stat(path + CorruptSuffix); !os.IsNotExist(err) {
+		t.Fatalf("newer file quarantined: %v", err)
+	}
+}
+
+func TestReadNothingUsable(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "settings.json")
+	if _, _, err := Read(path, validOK); !os.IsNotExist(err) {
+		t.Fatalf("missing file: err = %v, want not-exist", err)
+	}
+	writeAll(t, path, 1, "bad1", "bad2")
+	if _, _, err := Read(path, validOK); err == nil || err.Error() != "corrupt" {
+		t.Fatalf("err = %v, want the primary's error", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("primary moved without a usable backup: %v", err)
+	}
+}
+
+func TestRemoveDeletesBackups(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "trace.rtrace")
+	writeAll(t, path, 2, "ok1", "ok2", "ok3")
+	if err := Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{path, backupPath(path, 1), backupPath(path, 2)} {
+		if _, err := os.Stat(name); !os.IsNotExist(err) {
+			t.Fatalf("%s still exists: %v", name, err)
+		}
+	}
+	if err := Remove(path); !os.IsNotExist(err) {
+		t.Fatalf("second Remove: err = %v, want not-exist", err)
+	}
+}
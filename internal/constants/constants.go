@@ -22,10 +22,38 @@ const (
 
 	// Watcher defaults
 	DefaultPollIntervalSeconds = 5
+	// MaxPartialParseRetries bounds how many scans retry a stats file that still
+	// looks half-written before it is skipped.
+	MaxPartialParseRetries = 12
+	// NotifySettleMillis is how long a notified stats file must keep the same size
+	// before it is parsed.
+	NotifySettleMillis = 250
+	// IndexSaveDelaySeconds debounces writing the record index after changes.
+	IndexSaveDelaySeconds = 30
+	// ImportProgressEvery controls how often (in archive entries) import progress is emitted.
+	ImportProgressEvery = 25
+
+	// Scenario query paging
+	DefaultQueryLimit = 100
+	MaxQueryLimit     = 1000
 
 	// Mouse tracking defaults
 	DefaultMouseSampleHz = 125
 
+	// Local HTTP API
+	// DefaultAPIPort is the localhost port of the opt-in HTTP API.
+	DefaultAPIPort = 47811
+	// APIEventBuffer is how many events a slow stream client may lag behind before events are dropped.
+	APIEventBuffer = 64
+	// APIKeepAliveSeconds is the interval of comment lines that keep event streams open.
+	APIKeepAliveSeconds = 20
+
+	// Stream overlay
+	// OverlaySubdirName is the default overlay output folder in the config directory.
+	OverlaySubdirName = "overlay"
+	// OverlayDebounceMillis batches bursts of ingested runs into one rewrite of the overlay files.
+	OverlayDebounceMillis = 250
+
 	// Kovaak's Steam App information
 	KovaaksSteamAppID = 824270
 
@@ -33,6 +61,14 @@ const (
 	// Name of the app config folder in the user's home directory
 	ConfigDirName    = ".refleks"
 	TracesSubdirName = "traces"
+	// IndexFileName is the cache of parsed stats files in the config directory.
+	IndexFileName = "scenario-index.gob"
+	// SettingsBackups and TraceBackups are how many previous versions of
+	// settings.json and of each trace file are kept as ".bak.N" copies.
+	SettingsBackups = 3
+	TraceBackups    = 1
+	// PrimaryStatsSourceLabel labels records from the main StatsDir when several stats sources are watched.
+	PrimaryStatsSourceLabel = "Kovaak's"
 
 	// Default Kovaak's stats directory on Windows
 	DefaultWindowsKovaaksStatsDir = `C:\\Program Files (x86)\\Steam\\steamapps\\common\\FPSAimTrainer\\FPSAimTrainer\\stats`
@@ -0,0 +1,65 @@
+// Package events decouples event publishing and logging from the Wails
+// runtime so the ingestion pipeline can also run headless.
+package events
+
+import "fmt"
+
+// Level is the severity of a log message.
+type Level int
+
+const (
+	Debug Level = iota
+	Info
+	Warning
+	Error
+)
+
+// String returns the lower-case level name.
+func (l Level) String() string {
+	switch l {
+	case Debug:
+		return "debug"
+	case Info:
+		return "info"
+	case Warning:
+		return "warning"
+	case Error:
+		return "error"
+	}
+	return fmt.Sprintf("level(%d)", int(l))
+}
+
+// EventSink receives the named events and log messages published by the
+// watcher and app services. Implementations must be safe for concurrent use.
+type EventSink interface {
+	// Emit publishes an event; data is JSON-serializable.
+	Emit(name string, data any)
+	// Logf records a formatted log message.
+	Logf(level Level, format string, args ...any)
+}
+
+// multi fans out to several sinks in order.
+type multi []EventSink
+
+// Multi returns a sink that forwards everything to each of sinks.
+func Multi(sinks ...EventSink) EventSink {
+	out := make(multi, 0, len(sinks))
+	for _, s := range sinks {
+		if s != nil {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func (m multi) Emit(name string, data any) {
+	for _, s := range m {
+		s.Emit(name, data)
+	}
+}
+
+func (m multi) Logf(level Level, format string, args ...any) {
+	for _, s := range m {
+		s.Logf(level, format, args...)
+	}
+}
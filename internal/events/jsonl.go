@@ -0,0 +1,82 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// line is one JSON-lines record. Events set Event and Data; log messages set
+// Level and Message.
+type line struct {
+	Time    string `json:"time"`
+	Event   string `json:"event,omitempty"`
+	Data    any    `json:"data,omitempty"`
+	Level   string `json:"level,omitempty"`
+	Message string `json:"msg,omitempty"`
+}
+
+// JSONLines writes each event and log message as one JSON object per line.
+type JSONLines struct {
+	mu  sync.Mutex
+	w   io.Writer
+	enc *json.Encoder
+	// MinLevel drops log messages below it. Events are always written.
+	MinLevel Level
+}
+
+// NewJSONLines returns a sink writing to w.
+func NewJSONLines(w io.Writer) *JSONLines {
+	return &JSONLines{w: w, enc: json.NewEncoder(w), MinLevel: Info}
+}
+
+// OpenJSONLinesFile returns a sink appending to the file at path, creating it
+// and its directory when missing. Close the sink to close the file.
+func OpenJSONLinesFile(path string) (*JSONLines, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return NewJSONLines(f), nil
+}
+
+func (s *JSONLines) Emit(name string, data any) {
+	s.write(line{Time: now(), Event: name, Data: data})
+}
+
+func (s *JSONLines) Logf(level Level, format string, args ...any) {
+	if level < s.MinLevel {
+		return
+	}
+	s.write(line{Time: now(), Level: level.String(), Message: fmt.Sprintf(format, args...)})
+}
+
+func (s *JSONLines) write(l line) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.enc.Encode(l); err != nil {
+		// Keep the stream readable when data can't be encoded.
+		_ = s.enc.Encode(line{Time: l.Time, Level: Error.String(), Message: fmt.Sprintf("encode %s: %v", l.Event, err)})
+	}
+}
+
+// Close closes the underlying writer when it is an io.Closer.
+func (s *JSONLines) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if c, ok := s.w.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+func now() string {
+	return time.Now().Format(time.RFC3339Nano)
+}
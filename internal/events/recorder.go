@@ -0,0 +1,91 @@
+package events
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Event is one emitted event as kept by a Recorder.
+type Event struct {
+	Time time.Time
+	Name string
+	Data any
+}
+
+// LogEntry is one log message as kept by a Recorder.
+type LogEntry struct {
+	Time    time.Time
+	Level   Level
+	Message string
+}
+
+// Recorder keeps everything published to it in memory. It is meant for tests
+// and for callers that inspect the events after running the pipeline.
+type Recorder struct {
+	mu     sync.Mutex
+	events []Event
+	logs   []LogEntry
+}
+
+// NewRecorder returns an empty Recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+func (r *Recorder) Emit(name string, data any) {
+	r.mu.Lock()
+	r.events = append(r.events, Event{Time: time.Now(), Name: name, Data: data})
+	r.mu.Unlock()
+}
+
+func (r *Recorder) Logf(level Level, format string, args ...any) {
+	msg := fmt.Sprintf(format, args...)
+	r.mu.Lock()
+	r.logs = append(r.logs, LogEntry{Time: time.Now(), Level: level, Message: msg})
+	r.mu.Unlock()
+}
+
+// Events returns the recorded events, oldest first. When names are given,
+// only events with one of those names are returned.
+func (r *Recorder) Events(names ...string) []Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]Event, 0, len(r.events))
+	for _, e := range r.events {
+		if len(names) == 0 || contains(names, e.Name) {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// Logs returns the recorded log messages at or above min, oldest first.
+func (r *Recorder) Logs(min Level) []LogEntry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]LogEntry, 0, len(r.logs))
+	for _, l := range r.logs {
+		if l.Level >= min {
+			out = append(out, l)
+		}
+	}
+	return out
+}
+
+// Reset drops everything recorded so far.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	r.events = nil
+	r.logs = nil
+	r.mu.Unlock()
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
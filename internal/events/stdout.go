@@ -0,0 +1,45 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// Stdout prints events to standard output and log messages to standard
+// error in a human-readable form, for running the pipeline from a terminal.
+type Stdout struct {
+	mu     sync.Mutex
+	out    io.Writer
+	errOut io.Writer
+	// MinLevel drops log messages below it.
+	MinLevel Level
+}
+
+// NewStdout returns a sink printing log messages at or above min.
+func NewStdout(min Level) *Stdout {
+	return &Stdout{out: os.Stdout, errOut: os.Stderr, MinLevel: min}
+}
+
+func (s *Stdout) Emit(name string, data any) {
+	b, err := json.Marshal(data)
+	if err != nil {
+		b = []byte(fmt.Sprintf("%q", err.Error()))
+	}
+	s.mu.Lock()
+	fmt.Fprintf(s.out, "%s %s %s\n", time.Now().Format("15:04:05"), name, b)
+	s.mu.Unlock()
+}
+
+func (s *Stdout) Logf(level Level, format string, args ...any) {
+	if level < s.MinLevel {
+		return
+	}
+	msg := fmt.Sprintf(format, args...)
+	s.mu.Lock()
+	fmt.Fprintf(s.errOut, "%s [%s] %s\n", time.Now().Format("15:04:05"), level, msg)
+	s.mu.Unlock()
+}
@@ -0,0 +1,40 @@
+// Package wailssink implements events.EventSink on top of the Wails runtime.
+// It lives apart from package events so headless users of the watcher do not
+// link the Wails runtime.
+package wailssink
+
+import (
+	"context"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+
+	"refleks/internal/events"
+)
+
+// Sink publishes to the frontend and the application log through the Wails
+// runtime. The context must be the one passed to the app's startup hook.
+type Sink struct {
+	ctx context.Context
+}
+
+// New returns a sink bound to a Wails application context.
+func New(ctx context.Context) *Sink {
+	return &Sink{ctx: ctx}
+}
+
+func (s *Sink) Emit(name string, data any) {
+	runtime.EventsEmit(s.ctx, name, data)
+}
+
+func (s *Sink) Logf(level events.Level, format string, args ...any) {
+	switch level {
+	case events.Debug:
+		runtime.LogDebugf(s.ctx, format, args...)
+	case events.Info:
+		runtime.LogInfof(s.ctx, format, args...)
+	case events.Warning:
+		runtime.LogWarningf(s.ctx, format, args...)
+	default:
+		runtime.LogErrorf(s.ctx, format, args...)
+	}
+}
@@ -0,0 +1,155 @@
+package importer
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+)
+
+// ErrUnsupportedArchive is returned for archive formats we cannot read.
+var ErrUnsupportedArchive = errors.New("unsupported archive format (expected .zip, .tar.gz, .tgz or .tar)")
+
+// EntryFunc is called for every stats CSV entry of an archive. name is the
+// entry path inside the archive; r is only valid for the duration of the call.
+type EntryFunc func(name string, r io.Reader) error
+
+// entrySep separates the archive path from the entry name in EntryPath.
+const entrySep = "!/"
+
+// EntryPath returns a stable identifier for an archive entry, used as the
+// FilePath of imported records (e.g. "C:\backup.zip!/stats/X Stats.csv").
+func EntryPath(archivePath, entry string) string {
+	return archivePath + entrySep + strings.TrimPrefix(entry, "/")
+}
+
+// SplitEntryPath splits a path made by EntryPath into the archive path and
+// the entry name. ok is false for plain file paths.
+func SplitEntryPath(p string) (archivePath, entry string, ok bool) {
+	for i := 0; ; i += len(entrySep) {
+		j := strings.Index(p[i:], entrySep)
+		if j < 0 {
+			return "", "", false
+		}
+		i += j
+		if isArchive(p[:i]) {
+			return p[:i], p[i+len(entrySep):], true
+		}
+	}
+}
+
+// ReadEntry returns the content of one stats CSV entry of an archive.
+func ReadEntry(archivePath, entry string) ([]byte, error) {
+	var data []byte
+	errFound := errors.New("entry found")
+	err := WalkStats(archivePath, func(name string, r io.Reader) error {
+		if strings.TrimPrefix(name, "/") != entry {
+			return nil
+		}
+		var err error
+		if data, err = io.ReadAll(r); err != nil {
+			return err
+		}
+		return errFound
+	})
+	switch {
+	case errors.Is(err, errFound):
+		return data, nil
+	case err == nil:
+		return nil, fmt.Errorf("%s: %w", EntryPath(archivePath, entry), os.ErrNotExist)
+	}
+	return nil, err
+}
+
+// IsStatsEntry reports whether an archive entry looks like a Kovaak's stats CSV.
+func IsStatsEntry(name string) bool {
+	return strings.HasSuffix(strings.ToLower(path.Base(name)), " stats.csv")
+}
+
+// WalkStats streams every stats CSV entry of the archive at archivePath to fn.
+// Entries are visited in archive order. Returning an error from fn aborts the walk.
+func WalkStats(archivePath string, fn EntryFunc) error {
+	lower := strings.ToLower(archivePath)
+	switch {
+	case strings.HasSuffix(lower, ".zip"):
+		return walkZip(archivePath, fn)
+	case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		return walkTar(archivePath, true, fn)
+	case strings.HasSuffix(lower, ".tar"):
+		return walkTar(archivePath, false, fn)
+	default:
+		return ErrUnsupportedArchive
+	}
+}
+
+// isArchive reports whether WalkStats can read the file at p.
+func isArchive(p string) bool {
+	lower := strings.ToLower(p)
+	for _, ext := range []string{".zip", ".tar.gz", ".tgz", ".tar"} {
+		if strings.HasSuffix(lower, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+func walkZip(archivePath string, fn EntryFunc) error {
+	zr, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return err
+	}
+	defer zr.Close()
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() || !IsStatsEntry(f.Name) {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return fmt.Errorf("%s: %w", f.Name, err)
+		}
+		err = fn(f.Name, rc)
+		rc.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func walkTar(archivePath string, gzipped bool, fn EntryFunc) error {
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	var r io.Reader = f
+	if gzipped {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		r = gz
+	}
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if hdr.Typeflag != tar.TypeReg || !IsStatsEntry(hdr.Name) {
+			continue
+		}
+		if err := fn(hdr.Name, tr); err != nil {
+			return err
+		}
+	}
+}
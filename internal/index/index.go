@@ -0,0 +1,195 @@
+package index
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"refleks/internal/atomicfile"
+	"refleks/internal/models"
+)
+
+// Version is the on-disk format version. Bump it whenever ScenarioRecord or
+// the stats derived while parsing change, so stale caches are rebuilt.
+const Version = 3
+
+// ErrVersion is returned by Open when the index was written by another version.
+var ErrVersion = errors.New("index version mismatch")
+
+// Entry is the cached parse result of one stats file. Size and ModTime are
+// the file state the record was parsed from. Record only holds the summary
+// fields; kill rows and the weapon table are read from the stats file when needed.
+type Entry struct {
+	Size    int64
+	ModTime int64 // unix nanoseconds
+	Record  models.ScenarioRecord
+	// Archived marks a run imported from a backup archive. It has no file on
+	// disk, so Retain keeps it and only a version bump drops it.
+	Archived bool
+}
+
+// file is the gob-encoded content of the index file.
+type file struct {
+	Version int
+	Entries map[string]Entry
+}
+
+// Index caches parsed scenario records keyed by stats file path. A cached
+// record is only returned while the file keeps the same size and mtime.
+type Index struct {
+	mu      sync.Mutex
+	path    string
+	entries map[string]Entry
+	dirty   bool
+}
+
+// Open loads the index stored at path. A missing file yields an empty index.
+// When the file is unreadable or from another version, an empty index is
+// returned along with the error so callers can log it and carry on.
+func Open(path string) (*Index, error) {
+	x := &Index{path: path, entries: make(map[string]Entry)}
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return x, nil
+		}
+		return x, err
+	}
+	defer f.Close()
+	var data file
+	if err := gob.NewDecoder(f).Decode(&data); err != nil {
+		return x, fmt.Errorf("decode %s: %w", path, err)
+	}
+	if data.Version != Version {
+		return x, fmt.Errorf("%w: %s has v%d, want v%d", ErrVersion, path, data.Version, Version)
+	}
+	if data.Entries != nil {
+		x.entries = data.Entries
+	}
+	return x, nil
+}
+
+// Path returns the file the index is stored in.
+func (x *Index) Path() string { return x.path }
+
+// Len returns the number of cached records.
+func (x *Index) Len() int {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	return len(x.entries)
+}
+
+// Get returns the cached record for path if the file still has the given
+// size and modification time.
+func (x *Index) Get(path string, size int64, modTime time.Time) (models.ScenarioRecord, bool) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	e, ok := x.entries[path]
+	if !ok || e.Size != size || e.ModTime != modTime.UnixNano() {
+		return models.ScenarioRecord{}, false
+	}
+	return e.Record, true
+}
+
+// Record returns the cached record of path regardless of the file state.
+func (x *Index) Record(path string) (models.ScenarioRecord, bool) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	e, ok := x.entries[path]
+	return e.Record, ok
+}
+
+// Records returns every cached record, in no particular order.
+func (x *Index) Records() []models.ScenarioRecord {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	out := make([]models.ScenarioRecord, 0, len(x.entries))
+	for _, e := range x.entries {
+		out = append(out, e.Record)
+	}
+	return out
+}
+
+// summary returns rec without its kill rows, weapon table and mouse trace, so
+// the index stays small enough to load at startup.
+func summary(rec models.ScenarioRecord) models.ScenarioRecord {
+	rec.Events, rec.Kills, rec.Weapons, rec.MouseTrace = nil, nil, nil, nil
+	return rec
+}
+
+// Put caches the summary of rec as the parse result of path at the given
+// file state.
+func (x *Index) Put(path string, size int64, modTime time.Time, rec models.ScenarioRecord) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.entries[path] = Entry{Size: size, ModTime: modTime.UnixNano(), Record: summary(rec)}
+	x.dirty = true
+}
+
+// PutArchived stores the summary of rec as a run imported from a backup archive.
+func (x *Index) PutArchived(path string, rec models.ScenarioRecord) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.entries[path] = Entry{Record: summary(rec), Archived: true}
+	x.dirty = true
+}
+
+// Archived returns the records of every imported run, in no particular order.
+func (x *Index) Archived() []models.ScenarioRecord {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	var out []models.ScenarioRecord
+	for _, e := range x.entries {
+		if e.Archived {
+			out = append(out, e.Record)
+		}
+	}
+	return out
+}
+
+// Delete drops the cached record of path.
+func (x *Index) Delete(path string) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if _, ok := x.entries[path]; ok {
+		delete(x.entries, path)
+		x.dirty = true
+	}
+}
+
+// Retain drops every cached record whose path is not in keep, except
+// archived ones.
+func (x *Index) Retain(keep map[string]struct{}) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	for p, e := range x.entries {
+		if _, ok := keep[p]; !ok && !e.Archived {
+			delete(x.entries, p)
+			x.dirty = true
+		}
+	}
+}
+
+// Save writes the index to disk if it changed since it was opened or last
+// saved. The file is replaced atomically so a crash never leaves it truncated.
+func (x *Index) Save() error {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if !x.dirty {
+		return nil
+	}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(file{Version: Version, Entries: x.entries}); err != nil {
+		return err
+	}
+	// The index is a rebuildable cache, so it keeps no backups.
+	if err := atomicfile.Write(x.path, buf.Bytes(), 0o644, 0); err != nil {
+		return err
+	}
+	x.dirty = false
+	return nil
+}
@@ -0,0 +1,13 @@
+package models
+
+// ImportProgress reports the state of a stats archive import. It is emitted
+// periodically as "ImportProgress" and returned as the final result.
+type ImportProgress struct {
+	Archive string `json:"archive"`
+	// Processed counts stats entries seen so far (imported + duplicates + failed).
+	Processed  int  `json:"processed"`
+	Imported   int  `json:"imported"`
+	Duplicates int  `json:"duplicates"`
+	Failed     int  `json:"failed"`
+	Done       bool `json:"done"`
+}
@@ -0,0 +1,37 @@
+package models
+
+// ScenarioQuery filters, sorts and pages scenario runs. Zero values leave a
+// filter unset.
+type ScenarioQuery struct {
+	// Name matches the scenario name case-insensitively: a glob when it
+	// contains *, ? or [, a substring otherwise.
+	Name string `json:"name,omitempty"`
+	// From and To bound the date played (RFC3339, inclusive).
+	From string `json:"from,omitempty"`
+	To   string `json:"to,omitempty"`
+	// MinCm360 and MaxCm360 bound the sensitivity; runs with an unknown cm/360 are excluded when set.
+	MinCm360 float64 `json:"minCm360,omitempty"`
+	MaxCm360 float64 `json:"maxCm360,omitempty"`
+	// Mode matches the play mode (e.g. "Challenge") case-insensitively.
+	Mode string `json:"mode,omitempty"`
+	// SessionID restricts results to the runs of one session.
+	SessionID string `json:"sessionId,omitempty"`
+	// Tags requires every listed user tag on the run's scenario.
+	Tags     []string `json:"tags,omitempty"`
+	MinScore *float64 `json:"minScore,omitempty"`
+
+	// SortBy is one of "date" (default), "score", "accuracy", "cm360" or "name".
+	SortBy string `json:"sortBy,omitempty"`
+	// Ascending flips the default descending order.
+	Ascending bool `json:"ascending,omitempty"`
+	Offset    int  `json:"offset,omitempty"`
+	// Limit defaults to DefaultQueryLimit and is capped at MaxQueryLimit.
+	Limit int `json:"limit,omitempty"`
+}
+
+// ScenarioPage is one page of query results. Total counts every match.
+type ScenarioPage struct {
+	Total  int               `json:"total"`
+	Offset int               `json:"offset"`
+	Items  []ScenarioSummary `json:"items"`
+}
@@ -3,14 +3,123 @@ package models
 import "time"
 
 type ScenarioRecord struct {
-	FilePath string         `json:"filePath"`
-	FileName string         `json:"fileName"`
-	Stats    map[string]any `json:"stats"`
-	Events   [][]string     `json:"events"`
-	// Optional mouse trace captured locally. Absent when disabled or unavailable.
+	FilePath string `json:"filePath"`
+	FileName string `json:"fileName"`
+	// Mode is the play mode from the filename (e.g. "Challenge", "Freeplay").
+	Mode string `json:"mode"`
+	// Source is the label of the stats directory (or archive) the run came from.
+	Source string `json:"source,omitempty"`
+	// ContentHash is the SHA-256 of the raw stats file, used to deduplicate copies.
+	ContentHash string `json:"contentHash,omitempty"`
+	// PlayedAt is the run end time in UTC (RFC3339). The "Date Played" stat keeps
+	// the same instant in the time zone of the source it was recorded in.
+	PlayedAt string `json:"playedAt"`
+	// Stats holds every key-value stat (known and unknown) plus derived fields.
+	Stats map[string]any `json:"stats"`
+	// ScenarioStats is the typed view of the known stats and derived fields.
+	ScenarioStats ScenarioStats `json:"scenarioStats"`
+	// Events holds the raw per-kill CSV rows as written by Kovaak's.
+	Events [][]string `json:"events"`
+	// Kills is the typed view of Events, resolved from the CSV header row.
+	Kills []KillEvent `json:"kills"`
+	// Weapons holds the per-weapon summary table with derived ratios.
+	Weapons []WeaponSummary `json:"weapons"`
+	// KillCount is the number of kill rows. It stays set when Events, Kills
+	// and Weapons are left out, as in the record index.
+	KillCount int `json:"killCount"`
+	// StartedAt is the run start time in UTC (RFC3339), derived from the
+	// "Challenge Start" stat or the first kill.
+	StartedAt string `json:"startedAt,omitempty"`
+	// HasTrace reports whether a mouse trace is stored for the run.
+	HasTrace bool `json:"hasTrace"`
+	// Optional mouse trace captured locally. Traces are kept on disk and only
+	// filled in by GetScenarioDetail.
 	MouseTrace []MousePoint `json:"mouseTrace,omitempty"`
 }
 
+// ScenarioSummary is the lightweight view of a ScenarioRecord used in lists
+// and events. It leaves out the per-kill rows, weapon table and mouse trace.
+type ScenarioSummary struct {
+	FilePath     string `json:"filePath"`
+	FileName     string `json:"fileName"`
+	ScenarioName string `json:"scenarioName"`
+	Mode         string `json:"mode"`
+	Source       string `json:"source,omitempty"`
+	// DatePlayed is the run end time (RFC3339) in the source's time zone;
+	// PlayedAt is the same instant in UTC.
+	DatePlayed    string         `json:"datePlayed"`
+	PlayedAt      string         `json:"playedAt"`
+	Stats         map[string]any `json:"stats"`
+	ScenarioStats ScenarioStats  `json:"scenarioStats"`
+	KillCount     int            `json:"killCount"`
+	HasTrace      bool           `json:"hasTrace"`
+}
+
+// ScenarioRemoved is the payload of the "ScenarioRemoved" event, emitted when a
+// stats file is deleted or moved away from the watched directories.
+type ScenarioRemoved struct {
+	FilePath string `json:"filePath"`
+	FileName string `json:"fileName"`
+}
+
+// ScenarioStats holds the well-known key-value stats of a Kovaak's stats file
+// along with the fields derived while ingesting it.
+type ScenarioStats struct {
+	Score          float64 `json:"score"`
+	HitCount       int     `json:"hitCount"`
+	MissCount      int     `json:"missCount"`
+	ChallengeStart string  `json:"challengeStart"`
+	SensScale      string  `json:"sensScale"`
+	HorizSens      float64 `json:"horizSens"`
+	VertSens       float64 `json:"vertSens"`
+	DPI            float64 `json:"dpi"`
+	FOV            float64 `json:"fov"`
+	Resolution     string  `json:"resolution"`
+	AvgFPS         float64 `json:"avgFps"`
+	PauseCount     int     `json:"pauseCount"`
+	// PauseDuration is the total paused time as reported by Kovaak's.
+	PauseDuration float64 `json:"pauseDuration"`
+	GameVersion   string  `json:"gameVersion"`
+	Hash          string  `json:"hash"`
+
+	// Derived fields (filled by the watcher).
+	Accuracy   float64 `json:"accuracy"`
+	RealAvgTTK float64 `json:"realAvgTtk"`
+	Cm360      float64 `json:"cm360"`
+}
+
+// KillEvent is a single per-kill row from a Kovaak's stats file.
+type KillEvent struct {
+	Index int `json:"index"`
+	// Timestamp is the raw time-of-day of the kill (e.g. "14:32:04.746").
+	Timestamp string `json:"timestamp"`
+	Bot       string `json:"bot"`
+	Weapon    string `json:"weapon"`
+	// TTK is the time to kill in seconds.
+	TTK            float64 `json:"ttk"`
+	Shots          int     `json:"shots"`
+	Hits           int     `json:"hits"`
+	Accuracy       float64 `json:"accuracy"`
+	DamageDone     float64 `json:"damageDone"`
+	DamagePossible float64 `json:"damagePossible"`
+	Efficiency     float64 `json:"efficiency"`
+	Cheated        bool    `json:"cheated"`
+	Overshots      int     `json:"overshots"`
+}
+
+// WeaponSummary is a row of the per-weapon summary table in a Kovaak's stats file.
+type WeaponSummary struct {
+	Weapon         string  `json:"weapon"`
+	Shots          int     `json:"shots"`
+	Hits           int     `json:"hits"`
+	DamageDone     float64 `json:"damageDone"`
+	DamagePossible float64 `json:"damagePossible"`
+	// Accuracy is Hits / Shots (derived).
+	Accuracy float64 `json:"accuracy"`
+	// Efficiency is DamageDone / DamagePossible (derived).
+	Efficiency float64 `json:"efficiency"`
+}
+
 type MousePoint struct {
 	TS time.Time `json:"ts"`
 	X  int32     `json:"x"`
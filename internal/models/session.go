@@ -0,0 +1,11 @@
+package models
+
+// Session is a group of scenario runs played without a break longer than the
+// configured session gap. Start and End are RFC3339 timestamps; Items is
+// ordered most-recent-first.
+type Session struct {
+	ID    string            `json:"id"`
+	Start string            `json:"start"`
+	End   string            `json:"end"`
+	Items []ScenarioSummary `json:"items"`
+}
@@ -2,7 +2,7 @@ package models
 
 // Settings represents persisted application settings.
 type Settings struct {
-	SteamInstallDir string `json:"steamInstallDir"`
+	SteamInstallDir      string   `json:"steamInstallDir"`
 	SteamIDOverride      string   `json:"steamIdOverride,omitempty"`
 	StatsDir             string   `json:"statsDir"`
 	TracesDir            string   `json:"tracesDir"`
@@ -12,4 +12,45 @@ type Settings struct {
 	MouseTrackingEnabled bool     `json:"mouseTrackingEnabled"`
 	MouseBufferMinutes   int      `json:"mouseBufferMinutes"`
 	MaxExistingOnStart   int      `json:"maxExistingOnStart"`
+
+	// StatsTimezone is the IANA time zone (e.g. "Europe/Berlin") of the clock that
+	// wrote the files in StatsDir. Empty uses the local time zone.
+	StatsTimezone string `json:"statsTimezone,omitempty"`
+	// StatsSources lists additional stats directories watched alongside StatsDir.
+	StatsSources []StatsSource `json:"statsSources,omitempty"`
+	// ScenarioTags maps a scenario name to user tags (e.g. "tracking") used to filter runs.
+	ScenarioTags map[string][]string `json:"scenarioTags,omitempty"`
+
+	// APIEnabled serves the local HTTP API on 127.0.0.1:APIPort. Requests must
+	// carry APIToken, which is generated when empty.
+	APIEnabled bool   `json:"apiEnabled"`
+	APIPort    int    `json:"apiPort"`
+	APIToken   string `json:"apiToken,omitempty"`
+
+	// OverlayEnabled rewrites text files in OverlayDir for stream overlays (e.g.
+	// OBS text sources) after every ingested run. OverlayFormats maps each file
+	// name to a Go text/template; empty uses the built-in formats.
+	OverlayEnabled bool              `json:"overlayEnabled"`
+	OverlayDir     string            `json:"overlayDir"`
+	OverlayFormats map[string]string `json:"overlayFormats,omitempty"`
+
+	// Trace retention, applied at startup and on demand. Zero disables a rule.
+	// TraceMaxAgeDays drops traces of older runs, TraceMaxTotalMB drops the
+	// oldest traces beyond that size, TraceKeepBest keeps only the N best-scoring
+	// traced runs per scenario and TraceDeleteOrphans drops traces whose stats
+	// file is gone.
+	TraceMaxAgeDays    int  `json:"traceMaxAgeDays"`
+	TraceMaxTotalMB    int  `json:"traceMaxTotalMB"`
+	TraceKeepBest      int  `json:"traceKeepBest"`
+	TraceDeleteOrphans bool `json:"traceDeleteOrphans"`
+}
+
+// StatsSource is a Kovaak's stats directory to watch. Label identifies the
+// source (e.g. "desktop", "laptop") on the records ingested from it. Timezone
+// is the IANA time zone the file timestamps were written in; empty means local.
+type StatsSource struct {
+	Path      string `json:"path"`
+	Label     string `json:"label,omitempty"`
+	Recursive bool   `json:"recursive,omitempty"`
+	Timezone  string `json:"timezone,omitempty"`
 }
@@ -0,0 +1,64 @@
+package models
+
+// TraceGCReport summarises a trace garbage collection pass. The per-rule
+// counts add up to Deleted.
+type TraceGCReport struct {
+	Deleted    int   `json:"deleted"`
+	FreedBytes int64 `json:"freedBytes"`
+	// Orphaned traces had no stats file left.
+	Orphaned int `json:"orphaned"`
+	// Expired traces were older than the maximum age.
+	Expired int `json:"expired"`
+	// BeyondBest traces were not among the best runs kept per scenario.
+	BeyondBest int `json:"beyondBest"`
+	// OverBudget traces were the oldest ones deleted to fit the size limit.
+	OverBudget int `json:"overBudget"`
+	// Corrupt counts damaged traces that had been replaced by their backup.
+	Corrupt int `json:"corrupt"`
+	// Failed counts traces that matched a rule but could not be deleted.
+	Failed         int   `json:"failed"`
+	Remaining      int   `json:"remaining"`
+	RemainingBytes int64 `json:"remainingBytes"`
+}
+
+// TraceInfo describes a stored mouse trace without its points.
+type TraceInfo struct {
+	// FileName is the stats file the trace belongs to; TraceFile is its name
+	// in the traces directory.
+	FileName     string `json:"fileName"`
+	TraceFile    string `json:"traceFile"`
+	ScenarioName string `json:"scenarioName,omitempty"`
+	// DatePlayed is RFC3339; it falls back to the file time when the trace has none.
+	DatePlayed string `json:"datePlayed"`
+	Points     int    `json:"points"`
+	DurationMs int64  `json:"durationMs"`
+	Bytes      int64  `json:"bytes"`
+	Version    int    `json:"version"`
+	// Legacy marks traces still stored in the old JSON format.
+	Legacy bool `json:"legacy,omitempty"`
+	// Error is set when the trace could not be read.
+	Error string `json:"error,omitempty"`
+}
+
+// TraceStats aggregates the trace store.
+type TraceStats struct {
+	Count           int    `json:"count"`
+	Scenarios       int    `json:"scenarios"`
+	TotalBytes      int64  `json:"totalBytes"`
+	TotalPoints     int64  `json:"totalPoints"`
+	TotalDurationMs int64  `json:"totalDurationMs"`
+	Legacy          int    `json:"legacy"`
+	Unreadable      int    `json:"unreadable"`
+	Oldest          string `json:"oldest,omitempty"`
+	Newest          string `json:"newest,omitempty"`
+}
+
+// TraceMigrationReport summarises a bulk rewrite of stored traces to the
+// current data version.
+type TraceMigrationReport struct {
+	Checked  int `json:"checked"`
+	Upgraded int `json:"upgraded"`
+	// Newer counts traces written by a newer app version; they are left as is.
+	Newer  int `json:"newer"`
+	Failed int `json:"failed"`
+}
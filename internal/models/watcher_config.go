@@ -8,4 +8,19 @@ type WatcherConfig struct {
 	PollInterval         time.Duration
 	ParseExistingOnStart bool
 	ParseExistingLimit   int
+	// RecentCap bounds how many runs are held in memory. Zero uses
+	// ParseExistingLimit (or a default when that is zero too); a negative value
+	// keeps every run, e.g. for one-off CLI loads.
+	RecentCap int
+	// ScanWorkers bounds how many files are parsed concurrently during the
+	// initial scan. Zero uses the number of CPUs.
+	ScanWorkers int
+	// Sources lists every stats directory to watch, including Path. When empty,
+	// Path is watched as the only, non-recursive source.
+	Sources []StatsSource
+	// IndexPath is where parsed records are cached between runs. When set,
+	// cached files skip parsing, files beyond ParseExistingLimit are parsed in
+	// the background and runs beyond the recent cap stay queryable. Empty
+	// disables the cache.
+	IndexPath string
 }
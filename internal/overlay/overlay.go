@@ -0,0 +1,249 @@
+// Package overlay renders live text files for stream overlays (e.g. OBS text
+// sources) from the ingested runs, using configurable Go templates.
+package overlay
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"refleks/internal/atomicfile"
+	"refleks/internal/constants"
+	"refleks/internal/events"
+	"refleks/internal/models"
+	"refleks/internal/query"
+)
+
+// DefaultFormats are the files written when no formats are configured.
+var DefaultFormats = map[string]string{
+	"last_run.txt": "{{.Scenario}}\n{{num .Score 1}} · {{pct .Accuracy}}",
+	"session.txt":  "Runs this session: {{.SessionRuns}}\nAvg: {{num .SessionAvgScore 1}} ({{.SessionScenarioRuns}} on this scenario)",
+	"pb.txt":       "PB: {{num .PB 1}}{{if .IsPB}} NEW PB!{{else}} ({{signed .DeltaToPB 1}}){{end}}",
+}
+
+// Data is what the templates are rendered with. Scores and averages refer to
+// the most recent run and its scenario and mode.
+type Data struct {
+	Scenario string
+	Mode     string
+	Score    float64
+	// Accuracy is a 0..1 ratio; use {{pct .Accuracy}} to print it.
+	Accuracy float64
+	PlayedAt time.Time
+	// PB is the best score including this run; PrevPB the best before it (0
+	// for a first run). DeltaToPB is Score - PrevPB and IsPB reports a new PB.
+	PB           float64
+	PrevPB       float64
+	DeltaToPB    float64
+	IsPB         bool
+	ScenarioRuns int
+	// SessionRuns counts every run in the current session; the Session*
+	// averages only cover the runs of this scenario in it.
+	SessionRuns         int
+	SessionScenarioRuns int
+	SessionAvgScore     float64
+	SessionAvgAccuracy  float64
+}
+
+var funcs = template.FuncMap{
+	"pct":    func(v float64) string { return fmt.Sprintf("%.1f%%", v*100) },
+	"num":    func(v float64, prec int) string { return fmt.Sprintf("%.*f", prec, v) },
+	"signed": func(v float64, prec int) string { return fmt.Sprintf("%+.*f", prec, v) },
+}
+
+// Compile parses formats (file name -> template). Nil or empty formats
+// compile DefaultFormats. File names must be plain names without directories.
+func Compile(formats map[string]string) (map[string]*template.Template, error) {
+	if len(formats) == 0 {
+		formats = DefaultFormats
+	}
+	out := make(map[string]*template.Template, len(formats))
+	for name, text := range formats {
+		if name == "" || name != filepath.Base(name) || strings.ContainsAny(name, `/\`) {
+			return nil, fmt.Errorf("invalid overlay file name %q", name)
+		}
+		t, err := template.New(name).Funcs(funcs).Option("missingkey=error").Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("overlay %s: %w", name, err)
+		}
+		out[name] = t
+	}
+	return out, nil
+}
+
+// Build computes the template data for last, the newest run, within its
+// session. best holds the runs of last's scenario and mode sorted by score,
+// best first (at least the top two), with Total counting all of them.
+func Build(last models.ScenarioSummary, best models.ScenarioPage, session *models.Session) Data {
+	d := Data{
+		Scenario:     last.ScenarioName,
+		Mode:         last.Mode,
+		Score:        last.ScenarioStats.Score,
+		Accuracy:     last.ScenarioStats.Accuracy,
+		ScenarioRuns: max(best.Total, 1),
+	}
+	d.PlayedAt, _ = time.Parse(time.RFC3339, last.PlayedAt)
+	for _, r := range best.Items {
+		if r.FilePath != last.FilePath {
+			d.PrevPB = r.ScenarioStats.Score
+			break
+		}
+	}
+	d.PB = max(d.PrevPB, d.Score)
+	d.DeltaToPB = d.Score - d.PrevPB
+	d.IsPB = d.ScenarioRuns > 1 && d.Score > d.PrevPB
+	if session != nil {
+		d.SessionRuns = len(session.Items)
+		var score, acc float64
+		for _, it := range session.Items {
+			if it.ScenarioName == last.ScenarioName && it.Mode == last.Mode {
+				d.SessionScenarioRuns++
+				score += it.ScenarioStats.Score
+				acc += it.ScenarioStats.Accuracy
+			}
+		}
+		if d.SessionScenarioRuns > 0 {
+			d.SessionAvgScore = score / float64(d.SessionScenarioRuns)
+			d.SessionAvgAccuracy = acc / float64(d.SessionScenarioRuns)
+		}
+	}
+	return d
+}
+
+// Backend supplies the runs and sessions the overlay is computed from.
+type Backend interface {
+	GetRecent(limit int) []models.ScenarioSummary
+	QueryScenarios(q models.ScenarioQuery) (models.ScenarioPage, error)
+	GetSessions(from, to string) ([]models.Session, error)
+}
+
+// data gathers what Build needs for the newest run: its scenario's two best
+// runs from the full history and the session containing it.
+func (w *Writer) data() (Data, bool, error) {
+	recent := w.backend.GetRecent(1)
+	if len(recent) == 0 {
+		return Data{}, false, nil
+	}
+	last := recent[0]
+	best, err := w.backend.QueryScenarios(models.ScenarioQuery{
+		Name:   query.ExactName(last.ScenarioName),
+		Mode:   last.Mode,
+		SortBy: "score",
+		Limit:  2,
+	})
+	if err != nil {
+		return Data{}, false, err
+	}
+	// Only the newest session ends at or after the newest run.
+	var current *models.Session
+	if list, err := w.backend.GetSessions(last.PlayedAt, ""); err == nil && len(list) > 0 {
+		current = &list[0]
+	}
+	return Build(last, best, current), true, nil
+}
+
+// Writer rewrites the overlay files after runs are ingested. It is an
+// events.EventSink so it can be attached next to the app's other sinks.
+type Writer struct {
+	backend Backend
+	log     events.EventSink
+
+	mu      sync.Mutex
+	enabled bool
+	dir     string
+	tmpls   map[string]*template.Template
+	timer   *time.Timer
+}
+
+// New returns a disabled Writer that logs failures to log.
+func New(backend Backend, log events.EventSink) *Writer {
+	return &Writer{backend: backend, log: log}
+}
+
+// Configure applies the overlay settings and rewrites the files when enabled.
+func (w *Writer) Configure(enabled bool, dir string, formats map[string]string) error {
+	tmpls, err := Compile(formats)
+	if err != nil {
+		return err
+	}
+	if enabled && strings.TrimSpace(dir) == "" {
+		return errors.New("overlay directory is empty")
+	}
+	w.mu.Lock()
+	w.enabled, w.dir, w.tmpls = enabled, dir, tmpls
+	w.mu.Unlock()
+	if enabled {
+		w.schedule()
+	}
+	return nil
+}
+
+// Emit schedules a rewrite when the set of runs changed.
+func (w *Writer) Emit(name string, _ any) {
+	switch name {
+	case "ScenarioAdded", "ScenarioUpdated", "ScenarioRemoved", "ScenariosLoaded":
+		w.schedule()
+	}
+}
+
+// Logf ignores log messages.
+func (w *Writer) Logf(events.Level, string, ...any) {}
+
+// schedule debounces rewrites so a burst of runs (and the session update that
+// follows each one) renders once.
+func (w *Writer) schedule() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.enabled {
+		return
+	}
+	if w.timer != nil {
+		w.timer.Stop()
+	}
+	w.timer = time.AfterFunc(constants.OverlayDebounceMillis*time.Millisecond, func() {
+		if err := w.Write(); err != nil {
+			w.log.Logf(events.Warning, "overlay write failed: %v", err)
+		}
+	})
+}
+
+// Write renders every overlay file now. Nothing is written before the first run.
+func (w *Writer) Write() error {
+	w.mu.Lock()
+	enabled, dir, tmpls := w.enabled, w.dir, w.tmpls
+	w.mu.Unlock()
+	if !enabled {
+		return nil
+	}
+	d, ok, err := w.data()
+	if err != nil || !ok {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	names := make([]string, 0, len(tmpls))
+	for name := range tmpls {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var errs []error
+	for _, name := range names {
+		var buf bytes.Buffer
+		if err := tmpls[name].Execute(&buf, d); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+		if err := atomicfile.Write(filepath.Join(dir, name), buf.Bytes(), 0o644, 0); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,93 @@
+package overlay
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"refleks/internal/events"
+	"refleks/internal/models"
+	"refleks/internal/watcher"
+)
+
+// watcherBackend adapts a Watcher to Backend the way the app service does.
+type watcherBackend struct{ w *watcher.Watcher }
+
+func (b watcherBackend) GetRecent(limit int) []models.ScenarioSummary { return b.w.GetRecent(limit) }
+
+func (b watcherBackend) QueryScenarios(q models.ScenarioQuery) (models.ScenarioPage, error) {
+	return b.w.QueryScenarios(q, nil)
+}
+
+func (b watcherBackend) GetSessions(from, to string) ([]models.Session, error) {
+	start, err := time.Parse(time.RFC3339, from)
+	if err != nil {
+		return nil, err
+	}
+	return b.w.GetSessions(start, time.Time{}), nil
+}
+
+// writeRuns writes one stats file per score, a minute apart and oldest first.
+func writeRuns(t *testing.T, dir, scenario string, start time.Time, scores ...int) {
+	t.Helper()
+	tmpl, err := os.ReadFile(filepath.Join("..", "..", "testdata", "stats", "VT 1w3ts Intermediate S5 - Challenge - 2025.10.02-18.36.37 Stats.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	scoreLine := regexp.MustCompile(`(?m)^Score:,.*$`)
+	nameLine := regexp.MustCompile(`(?m)^Scenario:,.*$`)
+	for i, score := range scores {
+		played := start.Add(time.Duration(i) * time.Minute)
+		name := fmt.Sprintf("%s - Challenge - %s Stats.csv", scenario, played.Format("2006.01.02-15.04.05"))
+		body := scoreLine.ReplaceAll(tmpl, []byte(fmt.Sprintf("Score:,%d.0", score)))
+		body = nameLine.ReplaceAll(body, []byte("Scenario:,"+scenario))
+		if err := os.WriteFile(filepath.Join(dir, name), body, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestWriteUsesFullHistory(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("USERPROFILE", os.Getenv("HOME"))
+	dir := t.TempDir()
+	start := time.Date(2025, 1, 1, 8, 0, 0, 0, time.Local)
+	// The PB is the oldest run, well outside the recent window. A scenario whose
+	// name contains the tracked one must not count towards it.
+	writeRuns(t, dir, "Gridshot", start, 5000, 1001, 1002, 1003, 1004, 1005)
+	writeRuns(t, dir, "Gridshot Ultimate", start.Add(-time.Hour), 9000)
+
+	w := watcher.New(events.NewRecorder(), models.WatcherConfig{
+		Path:      dir,
+		IndexPath: filepath.Join(t.TempDir(), "index.gob"),
+		RecentCap: 2,
+	})
+	if err := w.Scan(); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(w.GetRecent(0)); n != 2 {
+		t.Fatalf("recent holds %d runs, want 2", n)
+	}
+
+	out := t.TempDir()
+	ow := New(watcherBackend{w}, events.NewRecorder())
+	formats := map[string]string{"o.txt": "{{.Scenario}} {{num .Score 0}} pb={{num .PB 0}} prev={{num .PrevPB 0}} new={{.IsPB}} runs={{.ScenarioRuns}} session={{.SessionRuns}}/{{.SessionScenarioRuns}}"}
+	if err := ow.Configure(false, out, formats); err != nil {
+		t.Fatal(err)
+	}
+	ow.enabled, ow.dir = true, out
+	if err := ow.Write(); err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(filepath.Join(out, "o.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "Gridshot 1005 pb=5000 prev=5000 new=false runs=6 session=6/6"
+	if string(got) != want {
+		t.Fatalf("overlay = %q, want %q", got, want)
+	}
+}
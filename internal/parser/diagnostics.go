@@ -0,0 +1,65 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrPartialFile indicates a stats file that looks half-written (e.g. Kovaak's
+// is still writing it). Callers should retry later instead of discarding it.
+var ErrPartialFile = errors.New("stats file is incomplete")
+
+// Reasons reported for skipped lines.
+const (
+	ReasonMalformedCSV     = "malformed CSV"
+	ReasonNotKillEvent     = "row does not match the kill table"
+	ReasonNotWeaponSummary = "row does not match the weapon summary table"
+	ReasonMalformedKV      = "key-value line without ':,' separator"
+)
+
+// SkippedLine describes an input line that was ignored while parsing.
+type SkippedLine struct {
+	// Line is the 1-based line number in the decoded file.
+	Line   int    `json:"line"`
+	Text   string `json:"text"`
+	Reason string `json:"reason"`
+}
+
+// Diagnostics collects non-fatal findings produced while parsing a stats file.
+type Diagnostics struct {
+	SkippedLines []SkippedLine `json:"skippedLines,omitempty"`
+	// UnknownSections lists the header rows of CSV tables that were not recognized.
+	UnknownSections []string `json:"unknownSections,omitempty"`
+	// MissingKeyValues is set when the file has no key-value section at all.
+	MissingKeyValues bool `json:"missingKeyValues,omitempty"`
+	// TruncatedLastLine is set when the final line is not newline-terminated.
+	TruncatedLastLine bool `json:"truncatedLastLine,omitempty"`
+}
+
+// Empty reports whether no findings were recorded.
+func (d Diagnostics) Empty() bool {
+	return len(d.SkippedLines) == 0 && len(d.UnknownSections) == 0 && !d.MissingKeyValues && !d.TruncatedLastLine
+}
+
+// String returns a compact single-line summary suitable for logs.
+func (d Diagnostics) String() string {
+	var parts []string
+	if n := len(d.SkippedLines); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d skipped line(s)", n))
+	}
+	if len(d.UnknownSections) > 0 {
+		parts = append(parts, "unknown sections: "+strings.Join(d.UnknownSections, ", "))
+	}
+	if d.MissingKeyValues {
+		parts = append(parts, "missing key-value block")
+	}
+	if d.TruncatedLastLine {
+		parts = append(parts, "truncated final line")
+	}
+	return strings.Join(parts, "; ")
+}
+
+func (d *Diagnostics) skip(line int, text, reason string) {
+	d.SkippedLines = append(d.SkippedLines, SkippedLine{Line: line, Text: text, Reason: reason})
+}
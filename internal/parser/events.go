@@ -0,0 +1,125 @@
+package parser
+
+import (
+	"strconv"
+	"strings"
+
+	"refleks/internal/models"
+)
+
+// KillEvent is the typed representation of a per-kill CSV row.
+type KillEvent = models.KillEvent
+
+// Kill table column names as written by Kovaak's in the header row.
+const (
+	colKillIndex      = "kill #"
+	colTimestamp      = "timestamp"
+	colBot            = "bot"
+	colWeapon         = "weapon"
+	colTTK            = "ttk"
+	colShots          = "shots"
+	colHits           = "hits"
+	colAccuracy       = "accuracy"
+	colDamageDone     = "damage done"
+	colDamagePossible = "damage possible"
+	colEfficiency     = "efficiency"
+	colCheated        = "cheated"
+	colOvershots      = "overshots"
+)
+
+// defaultKillColumns is the column order used when a file has no kill header row.
+var defaultKillColumns = []string{
+	colKillIndex, colTimestamp, colBot, colWeapon, colTTK, colShots, colHits,
+	colAccuracy, colDamageDone, colDamagePossible, colEfficiency, colCheated, colOvershots,
+}
+
+// columnIndex maps a normalized header name to its position in a CSV record.
+type columnIndex map[string]int
+
+func newColumnIndex(header []string) columnIndex {
+	idx := make(columnIndex, len(header))
+	for i, h := range header {
+		key := normalizeHeader(h)
+		if key == "" {
+			continue
+		}
+		// Keep the first occurrence; later duplicates belong to trailing tables.
+		if _, ok := idx[key]; !ok {
+			idx[key] = i
+		}
+	}
+	return idx
+}
+
+// get returns the trimmed value of column key in rec, or "" when absent.
+func (c columnIndex) get(rec []string, key string) string {
+	i, ok := c[key]
+	if !ok || i >= len(rec) {
+		return ""
+	}
+	return strings.TrimSpace(rec[i])
+}
+
+func normalizeHeader(s string) string {
+	return strings.ToLower(strings.TrimSpace(s))
+}
+
+// isKillHeaderRow reports whether rec is the header row of the kill table.
+func isKillHeaderRow(rec []string) bool {
+	for _, h := range rec {
+		if normalizeHeader(h) == colKillIndex {
+			return true
+		}
+	}
+	return false
+}
+
+// toKillEvent converts a raw kill row into a KillEvent using the resolved columns.
+func toKillEvent(rec []string, cols columnIndex) KillEvent {
+	return KillEvent{
+		Index:          atoi(cols.get(rec, colKillIndex)),
+		Timestamp:      cols.get(rec, colTimestamp),
+		Bot:            cols.get(rec, colBot),
+		Weapon:         cols.get(rec, colWeapon),
+		TTK:            parseSeconds(cols.get(rec, colTTK)),
+		Shots:          atoi(cols.get(rec, colShots)),
+		Hits:           atoi(cols.get(rec, colHits)),
+		Accuracy:       atof(cols.get(rec, colAccuracy)),
+		DamageDone:     atof(cols.get(rec, colDamageDone)),
+		DamagePossible: atof(cols.get(rec, colDamagePossible)),
+		Efficiency:     atof(cols.get(rec, colEfficiency)),
+		Cheated:        parseBool(cols.get(rec, colCheated)),
+		Overshots:      atoi(cols.get(rec, colOvershots)),
+	}
+}
+
+// parseSeconds parses durations written as "18.905001s" (the suffix is optional).
+func parseSeconds(s string) float64 {
+	return atof(strings.TrimSuffix(strings.TrimSpace(s), "s"))
+}
+
+func parseBool(s string) bool {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "1", "true", "yes":
+		return true
+	}
+	return false
+}
+
+func atoi(s string) int {
+	if i, err := strconv.Atoi(strings.TrimSpace(s)); err == nil {
+		return i
+	}
+	// Some columns occasionally carry a fractional part; truncate it.
+	if f, err := strconv.ParseFloat(strings.TrimSpace(s), 64); err == nil {
+		return int(f)
+	}
+	return 0
+}
+
+func atof(s string) float64 {
+	if f, err := strconv.ParseFloat(strings.TrimSpace(s), 64); err == nil {
+		return f
+	}
+	return 0
+}
@@ -16,51 +16,128 @@ import (
 
 var (
 	// Example: "Air Tracking 180 - Challenge - 2025.09.09-16.57.00 Stats.csv"
-	filenameRe = regexp.MustCompile(`^(?P<name>.+?)\s-\s.*?-\s(?P<dt>\d{4}\.\d{2}\.\d{2}-\d{2}\.\d{2}\.\d{2})\sStats\.csv$`)
+	// The prefix is greedy so scenario names containing " - " stay intact; the
+	// mode is split off the prefix afterwards.
+	filenameRe = regexp.MustCompile(`^(?P<prefix>.+)\s-\s(?P<dt>\d{4}\.\d{2}\.\d{2}-\d{2}\.\d{2}\.\d{2})\sStats\.csv$`)
 	dtLayout   = "2006.01.02-15.04.05"
 )
 
+// Play modes written by Kovaak's in the stats filename.
+const (
+	ModeChallenge = "Challenge"
+	ModeFreeplay  = "Freeplay"
+)
+
+// modeSeparator separates the scenario name and the play mode in a filename.
+const modeSeparator = " - "
+
 // FilenameInfo represents parsed info from a stats filename.
 type FilenameInfo struct {
 	ScenarioName string
-	DatePlayed   time.Time
+	// Mode is the play mode segment, ModeChallenge or ModeFreeplay; empty when absent.
+	Mode       string
+	DatePlayed time.Time
 }
 
-// ParseFilename extracts scenario name and timestamp from a Kovaak's stats filename.
+// ParseFilename extracts scenario name, play mode and timestamp from a Kovaak's stats filename.
+// The timestamp is interpreted in the local time zone.
 func ParseFilename(filename string) (FilenameInfo, error) {
+	return ParseFilenameIn(filename, time.Local)
+}
+
+// ParseFilenameIn is like ParseFilename but interprets the timestamp in loc,
+// the time zone of the machine that wrote the file. Wall-clock times skipped
+// or repeated by a DST change resolve the same way as time.Date.
+func ParseFilenameIn(filename string, loc *time.Location) (FilenameInfo, error) {
 	base := filepath.Base(filename)
 	m := filenameRe.FindStringSubmatch(base)
 	if m == nil {
 		return FilenameInfo{}, fmt.Errorf("filename did not match expected format: %s", base)
 	}
-	name := m[1]
+	name, mode := splitNameMode(m[1])
+	if name == "" {
+		return FilenameInfo{}, fmt.Errorf("filename has no scenario name: %s", base)
+	}
 	dtStr := m[2]
-	t, err := time.ParseInLocation(dtLayout, dtStr, time.Local)
+	t, err := time.ParseInLocation(dtLayout, dtStr, loc)
 	if err != nil {
 		return FilenameInfo{}, err
 	}
-	return FilenameInfo{ScenarioName: name, DatePlayed: t}, nil
+	return FilenameInfo{ScenarioName: name, Mode: mode, DatePlayed: t}, nil
 }
 
-// ParseStatsFile parses a Kovaak's CSV stats file into events and stats map.
-// The file format contains a CSV section (events/kill rows) followed by a key-value section separated by ":,".
-func ParseStatsFile(path string) (events [][]string, stats map[string]any, err error) {
+// splitNameMode splits "<name> - <mode>" on the last separator, since the
+// scenario name itself may contain " - " but the mode never does. The last
+// segment only counts as the mode when it is a known one, so "A - B" without
+// a mode stays the scenario name "A - B".
+func splitNameMode(prefix string) (name, mode string) {
+	i := strings.LastIndex(prefix, modeSeparator)
+	if i < 0 {
+		return strings.TrimSpace(prefix), ""
+	}
+	last := strings.TrimSpace(prefix[i+len(modeSeparator):])
+	for _, m := range []string{ModeChallenge, ModeFreeplay} {
+		if strings.EqualFold(last, m) {
+			return strings.TrimSpace(prefix[:i]), m
+		}
+	}
+	return strings.TrimSpace(prefix), ""
+}
+
+// StatsFile is the parsed content of a Kovaak's stats CSV.
+type StatsFile struct {
+	// Events holds the raw per-kill rows.
+	Events [][]string
+	// Kills holds the typed per-kill rows, resolved from the kill table header.
+	Kills []KillEvent
+	// Weapons holds the per-weapon summary table rows.
+	Weapons []WeaponSummary
+	// Stats holds the key-value section with numeric values coerced to int/float64.
+	Stats map[string]any
+	// ScenarioStats holds the typed view of the known keys in Stats.
+	ScenarioStats ScenarioStats
+	// Diagnostics lists non-fatal findings (skipped lines, unknown sections, ...).
+	Diagnostics Diagnostics
+}
+
+// ParseStatsFile parses a Kovaak's CSV stats file from disk. See Parse.
+func ParseStatsFile(path string) (StatsFile, error) {
 	f, err := os.Open(path)
 	if err != nil {
-		return nil, nil, err
+		return StatsFile{}, err
 	}
 	defer f.Close()
+	return Parse(f, filepath.Base(path))
+}
 
-	wrapped, werr := WrapReaderWithUTF8(f)
+// Parse parses Kovaak's CSV stats content into events and stats map. name is
+// used for error context only (typically the stats file name).
+// The format contains a CSV section (events/kill rows) followed by a key-value section separated by ":,".
+// Content that looks half-written returns the partially parsed result along with an
+// error wrapping ErrPartialFile.
+func Parse(in io.Reader, name string) (StatsFile, error) {
+	wrapped, werr := WrapReaderWithUTF8(in)
 	if werr != nil {
-		return nil, nil, werr
+		return StatsFile{}, fmt.Errorf("%s: %w", name, werr)
 	}
 
 	// We'll read line by line to detect the transition from CSV to key-value section.
 	r := bufio.NewReader(wrapped)
 	var csvLines [][]string
-	var kvLines []string
+	var kills []KillEvent
+	var weapons []WeaponSummary
+	var weaponCols columnIndex
+	type kvLine struct {
+		n    int
+		text string
+	}
+	var kvLines []kvLine
+	var diag Diagnostics
 	isKV := false
+	// Resolve kill columns from the header row; fall back to Kovaak's default order.
+	cols := newColumnIndex(defaultKillColumns)
+	seenKillHeader := false
+	lineNo := 0
 
 	for {
 		line, readErr := r.ReadString('\n')
@@ -69,9 +146,11 @@ func ParseStatsFile(path string) (events [][]string, stats map[string]any, err e
 				break
 			}
 			// otherwise, process last line then break after loop
+			diag.TruncatedLastLine = true
 		} else if readErr != nil {
-			return nil, nil, readErr
+			return StatsFile{}, fmt.Errorf("%s: %w", name, readErr)
 		}
+		lineNo++
 		trimmed := strings.TrimRight(line, "\r\n")
 		if len(trimmed) == 0 {
 			// skip pure empty lines but preserve section state
@@ -86,22 +165,45 @@ func ParseStatsFile(path string) (events [][]string, stats map[string]any, err e
 			isKV = true
 		}
 		if isKV {
-			kvLines = append(kvLines, trimmed)
+			kvLines = append(kvLines, kvLine{n: lineNo, text: trimmed})
 		} else {
 			// Accumulate CSV raw line to be parsed via encoding/csv for robustness
 			// Use a temporary csv.Reader
 			rec, perr := parseCSVLine(trimmed)
 			if perr != nil {
-				return nil, nil, perr
+				diag.skip(lineNo, trimmed, ReasonMalformedCSV)
+				continue
+			}
+			if !seenKillHeader && isKillHeaderRow(rec) {
+				cols = newColumnIndex(rec)
+				seenKillHeader = true
+				continue
+			}
+			if isWeaponHeaderRow(rec) {
+				weaponCols = newColumnIndex(rec)
+				continue
+			}
+			if weaponCols != nil {
+				if isWeaponRow(rec, weaponCols) {
+					weapons = append(weapons, toWeaponSummary(rec, weaponCols))
+				} else {
+					diag.skip(lineNo, trimmed, ReasonNotWeaponSummary)
+				}
+				continue
 			}
 			// Only keep per-kill event rows. Kovaak's files may contain additional CSV tables
 			// (e.g., weapon summary) that should not be included in the events. We treat a row
-			// as an event when the first column is a numeric kill index and (optionally) the
-			// second column looks like a time-of-day.
-			if isKillEventRow(rec) {
+			// as an event when the kill index column is numeric and the timestamp column looks
+			// like a time-of-day.
+			if isKillEventRow(rec, cols) {
 				csvLines = append(csvLines, rec)
+				kills = append(kills, toKillEvent(rec, cols))
+			} else if first := strings.TrimSpace(rec[0]); first != "" && !isNumeric(first) {
+				// A non-numeric leading cell outside known tables starts a table we don't know.
+				diag.UnknownSections = append(diag.UnknownSections, first)
+			} else {
+				diag.skip(lineNo, trimmed, ReasonNotKillEvent)
 			}
-			// Otherwise, ignore non-event CSV rows (headers, summaries, etc.).
 		}
 
 		if errors.Is(readErr, io.EOF) {
@@ -109,15 +211,18 @@ func ParseStatsFile(path string) (events [][]string, stats map[string]any, err e
 		}
 	}
 
-	// Parse kv lines into a map[string]any
+	// Parse kv lines into a map[string]any, filling the typed view for known keys
 	statsMap := make(map[string]any, len(kvLines))
+	var st ScenarioStats
 	for _, l := range kvLines {
-		parts := strings.SplitN(l, ":,", 2)
+		parts := strings.SplitN(l.text, ":,", 2)
 		if len(parts) != 2 {
+			diag.skip(l.n, l.text, ReasonMalformedKV)
 			continue
 		}
 		key := strings.TrimSpace(parts[0])
 		val := strings.TrimSpace(parts[1])
+		applyKnownStat(&st, key, val)
 		// Try to coerce to int or float if applicable; otherwise keep as string
 		if i, ierr := strconv.Atoi(val); ierr == nil {
 			statsMap[key] = i
@@ -130,7 +235,17 @@ func ParseStatsFile(path string) (events [][]string, stats map[string]any, err e
 		statsMap[key] = val
 	}
 
-	return csvLines, statsMap, nil
+	sf := StatsFile{Events: csvLines, Kills: kills, Weapons: weapons, Stats: statsMap, ScenarioStats: st}
+	if len(kvLines) == 0 {
+		diag.MissingKeyValues = true
+	}
+	sf.Diagnostics = diag
+	// Every finished run has a Score key; without it the file is most likely
+	// still being written.
+	if _, ok := statsMap[keyScore]; !ok {
+		return sf, fmt.Errorf("%w: %s has no %s key", ErrPartialFile, name, keyScore)
+	}
+	return sf, nil
 }
 
 func parseCSVLine(line string) ([]string, error) {
@@ -144,22 +259,27 @@ func parseCSVLine(line string) ([]string, error) {
 	return rec, nil
 }
 
+func isNumeric(s string) bool {
+	_, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	return err == nil
+}
+
 func isInt(s string) bool {
 	_, err := strconv.Atoi(strings.TrimSpace(s))
 	return err == nil
 }
 
 // isKillEventRow returns true if the CSV record appears to be a per-kill event row.
-// Expectation: first field is an integer index, second field is a time-of-day like 17:56:30.198
-func isKillEventRow(rec []string) bool {
+// Expectation: the kill index column is an integer, the timestamp column is a time-of-day like 17:56:30.198
+func isKillEventRow(rec []string, cols columnIndex) bool {
 	if len(rec) < 2 {
 		return false
 	}
-	if !isInt(rec[0]) {
+	if !isInt(cols.get(rec, colKillIndex)) {
 		return false
 	}
 	// Optional sanity check: time-of-day in HH:MM:SS(.fraction)?
-	s := strings.TrimSpace(rec[1])
+	s := cols.get(rec, colTimestamp)
 	if len(s) < 7 { // too short to be HH:MM:SS
 		return false
 	}
@@ -0,0 +1,257 @@
+package parser
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const statsFixtures = "../../testdata/stats"
+
+// kvTail is a minimal key-value section of a finished run.
+const kvTail = "Kills:,2\nScore:,512.5\nScenario:,Inline\nHit Count:,2\nMiss Count:,1\n"
+
+func parseString(t *testing.T, content string) (StatsFile, error) {
+	t.Helper()
+	return Parse(strings.NewReader(content), "inline.csv")
+}
+
+func TestParseFixtures(t *testing.T) {
+	entries, err := os.ReadDir(statsFixtures)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var n int
+	for _, e := range entries {
+		if !strings.HasSuffix(e.Name(), " Stats.csv") {
+			continue
+		}
+		n++
+		sf, err := ParseStatsFile(filepath.Join(statsFixtures, e.Name()))
+		if err != nil {
+			t.Errorf("%s: %v", e.Name(), err)
+			continue
+		}
+		if len(sf.Kills) == 0 || len(sf.Kills) != len(sf.Events) {
+			t.Errorf("%s: %d kills for %d event rows", e.Name(), len(sf.Kills), len(sf.Events))
+		}
+		if kills, ok := sf.Stats["Kills"].(int); !ok || kills != len(sf.Kills) {
+			t.Errorf("%s: Kills stat %v, parsed %d kill rows", e.Name(), sf.Stats["Kills"], len(sf.Kills))
+		}
+		if len(sf.Weapons) == 0 {
+			t.Errorf("%s: no weapon summary", e.Name())
+		}
+		if sf.ScenarioStats.Score <= 0 || sf.Stats["Scenario"] == nil {
+			t.Errorf("%s: typed stats not filled: %+v", e.Name(), sf.ScenarioStats)
+		}
+		if len(sf.Diagnostics.SkippedLines) > 0 || sf.Diagnostics.MissingKeyValues {
+			t.Errorf("%s: unexpected diagnostics: %s", e.Name(), sf.Diagnostics)
+		}
+	}
+	if n == 0 {
+		t.Fatal("no stats fixtures found")
+	}
+}
+
+func TestParseFixtureValues(t *testing.T) {
+	sf, err := ParseStatsFile(filepath.Join(statsFixtures, "VT 1w3ts Intermediate S5 - Challenge - 2025.10.02-18.36.37 Stats.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sf.Kills) != 124 {
+		t.Fatalf("got %d kills, want 124", len(sf.Kills))
+	}
+	k := sf.Kills[51]
+	if k.Index != 52 || k.Timestamp != "18:36:02.244" || k.Weapon != "BB Gun" || k.TTK != 0.516 || k.Shots != 2 || k.Hits != 1 || k.Accuracy != 0.5 || k.DamagePossible != 2 {
+		t.Fatalf("kill 52 = %+v", k)
+	}
+	if len(sf.Weapons) != 1 {
+		t.Fatalf("got %d weapons, want 1", len(sf.Weapons))
+	}
+	if w := sf.Weapons[0]; w.Weapon != "BB Gun" || w.Shots != 130 || w.Hits != 124 || w.DamageDone != 124 || w.DamagePossible != 130 {
+		t.Fatalf("weapon = %+v", w)
+	}
+	st := sf.ScenarioStats
+	if st.Score != 1211.046631 || sf.Stats["Scenario"] != "VT 1w3ts Intermediate S5" || st.HitCount != 124 || st.MissCount != 6 || st.DPI != 800 {
+		t.Fatalf("scenario stats = %+v", st)
+	}
+	if sf.Stats["Sens Scale"] != "CSGO" || sf.Stats["Horiz Sens"] != 1.5 {
+		t.Fatalf("stats map: Sens Scale %v, Horiz Sens %v", sf.Stats["Sens Scale"], sf.Stats["Horiz Sens"])
+	}
+}
+
+func TestParseKillColumnsFromHeader(t *testing.T) {
+	// Columns reordered and one unknown column added.
+	in := "Weapon,Extra,Timestamp,Kill #,Shots,Hits,TTK\n" +
+		"Rifle,x,10:00:01.500,1,3,2,0.250000s\n" +
+		"Pistol,y,10:00:02.000,2,1,1,0.100000s\n" +
+		kvTail
+	sf, err := parseString(t, in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sf.Kills) != 2 {
+		t.Fatalf("got %d kills, want 2: %s", len(sf.Kills), sf.Diagnostics)
+	}
+	k := sf.Kills[0]
+	if k.Index != 1 || k.Timestamp != "10:00:01.500" || k.Weapon != "Rifle" || k.Shots != 3 || k.Hits != 2 || k.TTK != 0.25 {
+		t.Fatalf("kill 1 = %+v", k)
+	}
+	if k := sf.Kills[1]; k.Index != 2 || k.Weapon != "Pistol" {
+		t.Fatalf("kill 2 = %+v", k)
+	}
+}
+
+func TestParseKillColumnsDefaultOrder(t *testing.T) {
+	// Without a header row Kovaak's default column order applies.
+	in := "1,10:00:01.500,target,Rifle,0.25s,3,2,0.666667,2.0,3.0,0.666667,0,1\n" + kvTail
+	sf, err := parseString(t, in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sf.Kills) != 1 {
+		t.Fatalf("got %d kills, want 1", len(sf.Kills))
+	}
+	if k := sf.Kills[0]; k.Bot != "target" || k.Weapon != "Rifle" || k.DamageDone != 2 || k.Overshots != 1 || k.Cheated {
+		t.Fatalf("kill = %+v", k)
+	}
+}
+
+func TestParseWeaponSummary(t *testing.T) {
+	in := "Kill #,Timestamp,Bot,Weapon,TTK,Shots,Hits\n" +
+		"1,10:00:01.500,target,Rifle,0.2s,3,2\n" +
+		"\n" +
+		"Weapon,Shots,Hits,Damage Done,Damage Possible,,Sens Scale\n" +
+		"Rifle,10,7,70.0,100.0,\n" +
+		"Pistol,4,4,40.0,40.0,\n" +
+		"not a weapon row\n" +
+		kvTail
+	sf, err := parseString(t, in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sf.Weapons) != 2 {
+		t.Fatalf("got %d weapons, want 2", len(sf.Weapons))
+	}
+	if w := sf.Weapons[0]; w.Weapon != "Rifle" || w.Shots != 10 || w.Hits != 7 || w.DamageDone != 70 || w.DamagePossible != 100 {
+		t.Fatalf("weapon 1 = %+v", w)
+	}
+	if len(sf.Kills) != 1 {
+		t.Fatalf("weapon rows leaked into kills: %d kills", len(sf.Kills))
+	}
+	skipped := sf.Diagnostics.SkippedLines
+	if len(skipped) != 1 || skipped[0].Line != 7 || skipped[0].Reason != ReasonNotWeaponSummary {
+		t.Fatalf("skipped lines = %+v", skipped)
+	}
+}
+
+func TestParseDiagnostics(t *testing.T) {
+	in := "Kill #,Timestamp,Bot,Weapon,TTK,Shots,Hits\n" +
+		"1,10:00:01.500,target,Rifle,0.2s,3,2\n" +
+		"2,not-a-time,target,Rifle,0.2s,3,2\n" +
+		"\"unterminated,quote\n" +
+		"Mystery Table,1,2\n" +
+		"Kills:,1\n" +
+		"no separator here\n" +
+		"Score:,100.0"
+	sf, err := parseString(t, in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := sf.Diagnostics
+	if !d.TruncatedLastLine {
+		t.Error("TruncatedLastLine not set for a file without a final newline")
+	}
+	if d.MissingKeyValues {
+		t.Error("MissingKeyValues set with a key-value section present")
+	}
+	if len(d.UnknownSections) != 1 || d.UnknownSections[0] != "Mystery Table" {
+		t.Errorf("unknown sections = %v", d.UnknownSections)
+	}
+	want := map[int]string{3: ReasonNotKillEvent, 4: ReasonMalformedCSV, 7: ReasonMalformedKV}
+	if len(d.SkippedLines) != len(want) {
+		t.Fatalf("skipped lines = %+v", d.SkippedLines)
+	}
+	for _, s := range d.SkippedLines {
+		if want[s.Line] != s.Reason {
+			t.Errorf("line %d skipped as %q, want %q", s.Line, s.Reason, want[s.Line])
+		}
+	}
+	if d.Empty() || d.String() == "" {
+		t.Error("diagnostics reported as empty")
+	}
+	if sf.ScenarioStats.Score != 100 {
+		t.Errorf("score from the truncated last line = %v, want 100", sf.ScenarioStats.Score)
+	}
+
+	clean, err := parseString(t, "1,10:00:01.500,target,Rifle,0.2s,3,2\n"+kvTail)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !clean.Diagnostics.Empty() {
+		t.Errorf("clean input has diagnostics: %s", clean.Diagnostics)
+	}
+}
+
+func TestParsePartialFile(t *testing.T) {
+	// Kovaak's writes the kill rows first; the Score key only arrives at the end.
+	in := "Kill #,Timestamp,Bot,Weapon\n1,10:00:01.500,target,Rifle\n2,10:00:0"
+	sf, err := parseString(t, in)
+	if !errors.Is(err, ErrPartialFile) {
+		t.Fatalf("err = %v, want ErrPartialFile", err)
+	}
+	if len(sf.Kills) != 1 || !sf.Diagnostics.MissingKeyValues || !sf.Diagnostics.TruncatedLastLine {
+		t.Fatalf("partial result: %d kills, diagnostics %s", len(sf.Kills), sf.Diagnostics)
+	}
+
+	_, err = parseString(t, "Kills:,1\nScenario:,Inline\n")
+	if !errors.Is(err, ErrPartialFile) {
+		t.Fatalf("missing Score: err = %v, want ErrPartialFile", err)
+	}
+	if _, err := parseString(t, kvTail); err != nil {
+		t.Fatalf("complete file: %v", err)
+	}
+}
+
+func TestParseFilename(t *testing.T) {
+	tests := []struct {
+		file, name, mode string
+	}{
+		{"Air Tracking 180 - Challenge - 2025.09.09-16.57.00 Stats.csv", "Air Tracking 180", ModeChallenge},
+		{"Air Tracking 180 - Freeplay - 2025.09.09-16.57.00 Stats.csv", "Air Tracking 180", ModeFreeplay},
+		{"1w3ts - Reload - Challenge - 2025.09.09-16.57.00 Stats.csv", "1w3ts - Reload", ModeChallenge},
+		{"Gridshot - 2025.09.09-16.57.00 Stats.csv", "Gridshot", ""},
+		// Without a known mode the whole prefix is the scenario name.
+		{"1w3ts - Reload - 2025.09.09-16.57.00 Stats.csv", "1w3ts - Reload", ""},
+		{"dir/Gridshot - challenge - 2025.09.09-16.57.00 Stats.csv", "Gridshot", ModeChallenge},
+	}
+	want := time.Date(2025, 9, 9, 16, 57, 0, 0, time.UTC)
+	for _, tt := range tests {
+		info, err := ParseFilenameIn(tt.file, time.UTC)
+		if err != nil {
+			t.Errorf("%s: %v", tt.file, err)
+			continue
+		}
+		if info.ScenarioName != tt.name || info.Mode != tt.mode || !info.DatePlayed.Equal(want) {
+			t.Errorf("%s: got %q, %q, %v; want %q, %q, %v", tt.file, info.ScenarioName, info.Mode, info.DatePlayed, tt.name, tt.mode, want)
+		}
+	}
+
+	for _, bad := range []string{"Gridshot Stats.csv", "Gridshot - 2025.09.09 Stats.csv", " - 2025.09.09-16.57.00 Stats.csv", "notes.txt"} {
+		if info, err := ParseFilename(bad); err == nil {
+			t.Errorf("%q parsed as %+v", bad, info)
+		}
+	}
+
+	loc := time.FixedZone("UTC+9", 9*60*60)
+	info, err := ParseFilenameIn(tests[0].file, loc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !info.DatePlayed.Equal(want.Add(-9 * time.Hour)) {
+		t.Errorf("DatePlayed in UTC+9 = %v", info.DatePlayed.UTC())
+	}
+}
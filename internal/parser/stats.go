@@ -0,0 +1,65 @@
+package parser
+
+import "refleks/internal/models"
+
+// ScenarioStats is the typed view of the known key-value stats.
+type ScenarioStats = models.ScenarioStats
+
+// Known key-value section keys as written by Kovaak's.
+const (
+	keyScore          = "Score"
+	keyHitCount       = "Hit Count"
+	keyMissCount      = "Miss Count"
+	keyChallengeStart = "Challenge Start"
+	keySensScale      = "Sens Scale"
+	keyHorizSens      = "Horiz Sens"
+	keyVertSens       = "Vert Sens"
+	keyDPI            = "DPI"
+	keyFOV            = "FOV"
+	keyResolution     = "Resolution"
+	keyAvgFPS         = "Avg FPS"
+	keyPauseCount     = "Pause Count"
+	keyPauseDuration  = "Pause Duration"
+	keyGameVersion    = "Game Version"
+	keyHash           = "Hash"
+)
+
+// applyKnownStat stores a raw key-value pair on st when the key is known.
+// It reports whether the key was recognized.
+func applyKnownStat(st *ScenarioStats, key, val string) bool {
+	switch key {
+	case keyScore:
+		st.Score = atof(val)
+	case keyHitCount:
+		st.HitCount = atoi(val)
+	case keyMissCount:
+		st.MissCount = atoi(val)
+	case keyChallengeStart:
+		st.ChallengeStart = val
+	case keySensScale:
+		st.SensScale = val
+	case keyHorizSens:
+		st.HorizSens = atof(val)
+	case keyVertSens:
+		st.VertSens = atof(val)
+	case keyDPI:
+		st.DPI = atof(val)
+	case keyFOV:
+		st.FOV = atof(val)
+	case keyResolution:
+		st.Resolution = val
+	case keyAvgFPS:
+		st.AvgFPS = atof(val)
+	case keyPauseCount:
+		st.PauseCount = atoi(val)
+	case keyPauseDuration:
+		st.PauseDuration = atof(val)
+	case keyGameVersion:
+		st.GameVersion = val
+	case keyHash:
+		st.Hash = val
+	default:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,31 @@
+package parser
+
+import "refleks/internal/models"
+
+// WeaponSummary is the typed representation of a weapon summary CSV row.
+type WeaponSummary = models.WeaponSummary
+
+// Weapon summary table column names. Shots, hits and damage reuse the kill table names.
+const colWeaponName = "weapon"
+
+// isWeaponHeaderRow reports whether rec is the header row of the weapon summary table.
+func isWeaponHeaderRow(rec []string) bool {
+	return len(rec) > 0 && normalizeHeader(rec[0]) == colWeaponName
+}
+
+// isWeaponRow reports whether rec looks like a weapon summary row under the resolved columns.
+func isWeaponRow(rec []string, cols columnIndex) bool {
+	return cols.get(rec, colWeaponName) != "" && isInt(cols.get(rec, colShots))
+}
+
+// toWeaponSummary converts a raw weapon summary row using the resolved columns.
+// Derived ratios (accuracy, efficiency) are left for the caller to compute.
+func toWeaponSummary(rec []string, cols columnIndex) WeaponSummary {
+	return WeaponSummary{
+		Weapon:         cols.get(rec, colWeaponName),
+		Shots:          atoi(cols.get(rec, colShots)),
+		Hits:           atoi(cols.get(rec, colHits)),
+		DamageDone:     atof(cols.get(rec, colDamageDone)),
+		DamagePossible: atof(cols.get(rec, colDamagePossible)),
+	}
+}
@@ -0,0 +1,251 @@
+package query
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"refleks/internal/constants"
+	"refleks/internal/models"
+	"refleks/internal/parser"
+)
+
+// Options supplies the data a query needs beyond the records themselves.
+type Options struct {
+	// Tags maps a scenario name to its user tags.
+	Tags map[string][]string
+	// Session holds the file paths of the runs in q.SessionID, if set.
+	Session map[string]struct{}
+}
+
+// Run filters recs with q, sorts the matches and returns the requested page.
+func Run(recs []models.ScenarioRecord, q models.ScenarioQuery, opts Options) (models.ScenarioPage, error) {
+	m, err := newMatcher(q, opts)
+	if err != nil {
+		return models.ScenarioPage{}, err
+	}
+	less, err := lessFunc(q.SortBy)
+	if err != nil {
+		return models.ScenarioPage{}, err
+	}
+
+	var matches []models.ScenarioRecord
+	for _, rec := range recs {
+		if m.match(rec) {
+			matches = append(matches, rec)
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if q.Ascending {
+			return less(matches[i], matches[j])
+		}
+		return less(matches[j], matches[i])
+	})
+
+	offset := q.Offset
+	if offset < 0 {
+		offset = 0
+	}
+	limit := q.Limit
+	if limit <= 0 {
+		limit = constants.DefaultQueryLimit
+	}
+	if limit > constants.MaxQueryLimit {
+		limit = constants.MaxQueryLimit
+	}
+	page := models.ScenarioPage{Total: len(matches), Offset: offset, Items: []models.ScenarioSummary{}}
+	if offset < len(matches) {
+		end := offset + limit
+		if end > len(matches) {
+			end = len(matches)
+		}
+		page.Items = Summaries(matches[offset:end])
+	}
+	return page, nil
+}
+
+// Summarize returns the list view of rec.
+func Summarize(rec models.ScenarioRecord) models.ScenarioSummary {
+	date, _ := rec.Stats["Date Played"].(string)
+	return models.ScenarioSummary{
+		FilePath:      rec.FilePath,
+		FileName:      rec.FileName,
+		ScenarioName:  ScenarioName(rec),
+		Mode:          rec.Mode,
+		Source:        rec.Source,
+		DatePlayed:    date,
+		PlayedAt:      rec.PlayedAt,
+		Stats:         rec.Stats,
+		ScenarioStats: rec.ScenarioStats,
+		KillCount:     rec.KillCount,
+		HasTrace:      rec.HasTrace || len(rec.MouseTrace) > 0,
+	}
+}
+
+// Summaries returns the list views of recs, in the same order.
+func Summaries(recs []models.ScenarioRecord) []models.ScenarioSummary {
+	out := make([]models.ScenarioSummary, len(recs))
+	for i, rec := range recs {
+		out[i] = Summarize(rec)
+	}
+	return out
+}
+
+// matcher is a compiled ScenarioQuery filter.
+type matcher struct {
+	q        models.ScenarioQuery
+	name     string
+	glob     bool
+	from, to time.Time
+	tags     map[string][]string
+	session  map[string]struct{}
+}
+
+func newMatcher(q models.ScenarioQuery, opts Options) (*matcher, error) {
+	m := &matcher{q: q, name: strings.ToLower(strings.TrimSpace(q.Name)), tags: opts.Tags, session: opts.Session}
+	if strings.ContainsAny(m.name, "*?[") {
+		if _, err := path.Match(m.name, ""); err != nil {
+			return nil, fmt.Errorf("invalid name pattern %q: %w", q.Name, err)
+		}
+		m.glob = true
+	}
+	var err error
+	if m.from, err = parseBound(q.From); err != nil {
+		return nil, fmt.Errorf("invalid from: %w", err)
+	}
+	if m.to, err = parseBound(q.To); err != nil {
+		return nil, fmt.Errorf("invalid to: %w", err)
+	}
+	return m, nil
+}
+
+func (m *matcher) match(rec models.ScenarioRecord) bool {
+	q := m.q
+	if m.name != "" {
+		name := strings.ToLower(ScenarioName(rec))
+		if m.glob {
+			if ok, _ := path.Match(m.name, name); !ok {
+				return false
+			}
+		} else if !strings.Contains(name, m.name) {
+			return false
+		}
+	}
+	if !m.from.IsZero() || !m.to.IsZero() {
+		t := DatePlayed(rec)
+		if t.IsZero() || (!m.from.IsZero() && t.Before(m.from)) || (!m.to.IsZero() && t.After(m.to)) {
+			return false
+		}
+	}
+	if q.MinCm360 > 0 || q.MaxCm360 > 0 {
+		cm := rec.ScenarioStats.Cm360
+		if cm <= 0 || (q.MinCm360 > 0 && cm < q.MinCm360) || (q.MaxCm360 > 0 && cm > q.MaxCm360) {
+			return false
+		}
+	}
+	if q.Mode != "" && !strings.EqualFold(rec.Mode, q.Mode) {
+		return false
+	}
+	if q.SessionID != "" {
+		if _, ok := m.session[rec.FilePath]; !ok {
+			return false
+		}
+	}
+	if len(q.Tags) > 0 && !hasTags(m.tags[ScenarioName(rec)], q.Tags) {
+		return false
+	}
+	if q.MinScore != nil && rec.ScenarioStats.Score < *q.MinScore {
+		return false
+	}
+	return true
+}
+
+// hasTags reports whether have contains every tag in want (case-insensitive).
+func hasTags(have, want []string) bool {
+	for _, w := range want {
+		found := false
+		for _, h := range have {
+			if strings.EqualFold(strings.TrimSpace(h), strings.TrimSpace(w)) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// lessFunc returns the ascending ordering for a SortBy key.
+func lessFunc(key string) (func(a, b models.ScenarioRecord) bool, error) {
+	switch strings.ToLower(strings.TrimSpace(key)) {
+	case "", "date":
+		return func(a, b models.ScenarioRecord) bool { return DatePlayed(a).Before(DatePlayed(b)) }, nil
+	case "score":
+		return func(a, b models.ScenarioRecord) bool { return a.ScenarioStats.Score < b.ScenarioStats.Score }, nil
+	case "accuracy":
+		return func(a, b models.ScenarioRecord) bool { return a.ScenarioStats.Accuracy < b.ScenarioStats.Accuracy }, nil
+	case "cm360":
+		return func(a, b models.ScenarioRecord) bool { return a.ScenarioStats.Cm360 < b.ScenarioStats.Cm360 }, nil
+	case "name":
+		return func(a, b models.ScenarioRecord) bool {
+			return strings.ToLower(ScenarioName(a)) < strings.ToLower(ScenarioName(b))
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown sort key %q", key)
+}
+
+// ExactName returns a ScenarioQuery.Name pattern that matches only the
+// scenario called name (still case-insensitively) rather than every name
+// containing it. The first rune goes in a character class so the pattern is
+// always treated as a glob.
+func ExactName(name string) string {
+	var b strings.Builder
+	for i, r := range strings.TrimSpace(name) {
+		if i == 0 {
+			b.WriteString("[\\")
+			b.WriteRune(r)
+			b.WriteByte(']')
+			continue
+		}
+		if strings.ContainsRune(`*?[\`, r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// ScenarioName returns the scenario name of a run: the "Scenario" stat when
+// present, otherwise the name parsed from the file name.
+func ScenarioName(rec models.ScenarioRecord) string {
+	if s, ok := rec.Stats["Scenario"].(string); ok && strings.TrimSpace(s) != "" {
+		return strings.TrimSpace(s)
+	}
+	if info, err := parser.ParseFilename(rec.FileName); err == nil {
+		return info.ScenarioName
+	}
+	return rec.FileName
+}
+
+// DatePlayed returns the run end time: PlayedAt when set, otherwise the
+// "Date Played" stat of records cached before PlayedAt existed.
+func DatePlayed(rec models.ScenarioRecord) time.Time {
+	if t, err := time.Parse(time.RFC3339, rec.PlayedAt); err == nil {
+		return t
+	}
+	s, _ := rec.Stats["Date Played"].(string)
+	t, _ := time.Parse(time.RFC3339, s)
+	return t
+}
+
+// parseBound parses an optional RFC3339 range bound.
+func parseBound(v string) (time.Time, error) {
+	if strings.TrimSpace(v) == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, strings.TrimSpace(v))
+}
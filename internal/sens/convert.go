@@ -2,9 +2,10 @@ package sens
 
 import (
 	"math"
+	"sort"
 
 	"refleks/internal/constants"
-	"refleks/internal/util"
+	"refleks/internal/models"
 )
 
 // Input contains the raw sensitivity information extracted from a stats file.
@@ -44,20 +45,44 @@ func Cm360(scale string, horizSens, dpi float64) (cm float64, ok bool) {
 	}
 }
 
-// Cm360FromStats extracts the needed values from a generic stats map and computes cm/360.
-// Expected keys (Kovaak's exports):
-//
-//	"Sens Scale" (string), "Horiz Sens" (number), "DPI" (number)
-//
-// Returns (0,false) when not enough information is present or scale unsupported.
-func Cm360FromStats(stats map[string]any) (float64, bool) {
-	if stats == nil {
+// Cm360FromScenarioStats computes cm/360 from the typed stats of a scenario.
+func Cm360FromScenarioStats(st models.ScenarioStats) (float64, bool) {
+	return Cm360(st.SensScale, st.HorizSens, st.DPI)
+}
+
+// FromCm360 converts a cm/360 value into a sensitivity on scale; it is the
+// inverse of Cm360 and has the same DPI requirements.
+func FromCm360(scale string, cm, dpi float64) (sens float64, ok bool) {
+	if !isFinitePositive(cm) {
+		return 0, false
+	}
+	switch scale {
+	case "cm/360":
+		return cm, true
+	case "in/360":
+		return cm / 2.54, true
+	default:
+		if !isFinitePositive(dpi) {
+			return 0, false
+		}
+		if yaw, ok := yawByScale[scale]; ok && yaw > 0 {
+			val := 360.0 / (dpi * cm * yaw) * 2.54
+			if isFinitePositive(val) {
+				return val, true
+			}
+		}
 		return 0, false
 	}
-	scale, _ := stats["Sens Scale"].(string)
-	s := util.ToFloat(stats["Horiz Sens"]) // treat as horiz sens across scales
-	dpi := util.ToFloat(stats["DPI"])
-	return Cm360(scale, s, dpi)
+}
+
+// Scales returns the supported sensitivity scale names, sorted.
+func Scales() []string {
+	out := []string{"cm/360", "in/360"}
+	for s := range yawByScale {
+		out = append(out, s)
+	}
+	sort.Strings(out)
+	return out
 }
 
 // Strict mapping from scale value to yaw (deg per count) for supported games.
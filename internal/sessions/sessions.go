@@ -0,0 +1,231 @@
+package sessions
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Run is the part of a scenario run needed to place it in a session.
+type Run struct {
+	FilePath string
+	Start    time.Time
+	End      time.Time
+}
+
+// Session is a group of runs whose consecutive end times are at most the gap
+// apart. Runs are ordered oldest-first.
+type Session struct {
+	ID    string
+	Start time.Time
+	End   time.Time
+	Runs  []Run
+}
+
+// Group splits runs into sessions, oldest-first. runs need not be sorted.
+func Group(runs []Run, gap time.Duration) []Session {
+	sorted := append([]Run(nil), runs...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if !sorted[i].End.Equal(sorted[j].End) {
+			return sorted[i].End.Before(sorted[j].End)
+		}
+		return sorted[i].FilePath < sorted[j].FilePath
+	})
+	var out []Session
+	for _, r := range sorted {
+		if n := len(out); n > 0 && r.End.Sub(out[n-1].lastEnd()) <= gap {
+			out[n-1].add(r)
+			continue
+		}
+		out = append(out, newSession(r))
+	}
+	return out
+}
+
+func newSession(r Run) Session {
+	return Session{ID: fmt.Sprintf("sess-%d", r.End.UnixMilli()), Start: r.Start, End: r.End, Runs: []Run{r}}
+}
+
+// add appends r (the newest run) and widens the session bounds.
+func (s *Session) add(r Run) {
+	s.Runs = append(s.Runs, r)
+	if r.Start.Before(s.Start) {
+		s.Start = r.Start
+	}
+	if r.End.After(s.End) {
+		s.End = r.End
+	}
+}
+
+func (s *Session) lastEnd() time.Time {
+	return s.Runs[len(s.Runs)-1].End
+}
+
+// Tracker keeps sessions up to date as runs arrive. Runs newer than every
+// known run extend or start the latest session in place; anything else
+// (imports, removals, edits) regroups all runs.
+type Tracker struct {
+	mu       sync.Mutex
+	gap      time.Duration
+	runs     map[string]Run
+	sessions []Session // oldest-first
+	// ended reports whether the latest session was already reported as ended.
+	ended bool
+}
+
+// NewTracker returns an empty tracker splitting sessions on gap.
+func NewTracker(gap time.Duration) *Tracker {
+	return &Tracker{gap: gap, runs: make(map[string]Run)}
+}
+
+// SetGap changes the session gap and regroups the known runs.
+func (t *Tracker) SetGap(gap time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.gap = gap
+	t.regroup()
+}
+
+// Reset replaces all known runs. No session is reported as started; only a
+// latest session still within the gap of now may later be reported as ended.
+func (t *Tracker) Reset(runs []Run) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.runs = make(map[string]Run, len(runs))
+	for _, r := range runs {
+		t.runs[r.FilePath] = r
+	}
+	t.regroup()
+}
+
+// Add records a new run. When it starts a new latest session, started is that
+// session and ended is the previous one unless it was already reported.
+func (t *Tracker) Add(r Run) (started, ended *Session) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	_, known := t.runs[r.FilePath]
+	t.runs[r.FilePath] = r
+	n := len(t.sessions)
+	if known || (n > 0 && r.End.Before(t.sessions[n-1].lastEnd())) {
+		t.regroup()
+		return nil, nil
+	}
+	if n > 0 && r.End.Sub(t.sessions[n-1].lastEnd()) <= t.gap {
+		t.sessions[n-1].add(r)
+		t.ended = false
+		return nil, nil
+	}
+	if n > 0 && !t.ended {
+		prev := t.sessions[n-1]
+		ended = &prev
+	}
+	t.sessions = append(t.sessions, newSession(r))
+	t.ended = false
+	s := t.sessions[n]
+	return &s, ended
+}
+
+// AddAll records several runs at once (e.g. an import) and regroups once.
+func (t *Tracker) AddAll(runs []Run) {
+	if len(runs) == 0 {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, r := range runs {
+		t.runs[r.FilePath] = r
+	}
+	t.regroup()
+}
+
+// Remove forgets the run stored for path.
+func (t *Tracker) Remove(path string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.runs[path]; !ok {
+		return
+	}
+	delete(t.runs, path)
+	t.regroup()
+}
+
+// Replace swaps the run stored for oldPath with r (a rename or rewrite).
+func (t *Tracker) Replace(oldPath string, r Run) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.runs, oldPath)
+	t.runs[r.FilePath] = r
+	t.regroup()
+}
+
+// Expire reports the latest session as ended once now is more than the gap
+// past its last run. It returns the session the first time it ends; otherwise
+// wait is how long until it may end (zero when there is nothing to wait for).
+func (t *Tracker) Expire(now time.Time) (ended *Session, wait time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	n := len(t.sessions)
+	if n == 0 || t.ended {
+		return nil, 0
+	}
+	last := t.sessions[n-1]
+	if idle := now.Sub(last.lastEnd()); idle <= t.gap {
+		return nil, t.gap - idle + time.Second
+	}
+	t.ended = true
+	return &last, 0
+}
+
+// Session returns the session with the given id.
+func (t *Tracker) Session(id string) (Session, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, s := range t.sessions {
+		if s.ID == id {
+			s.Runs = append([]Run(nil), s.Runs...)
+			return s, true
+		}
+	}
+	return Session{}, false
+}
+
+// Sessions returns the sessions overlapping [from, to], most-recent-first.
+// A zero from or to leaves that side unbounded.
+func (t *Tracker) Sessions(from, to time.Time) []Session {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var out []Session
+	for i := len(t.sessions) - 1; i >= 0; i-- {
+		s := t.sessions[i]
+		if !from.IsZero() && s.End.Before(from) {
+			continue
+		}
+		if !to.IsZero() && s.Start.After(to) {
+			continue
+		}
+		s.Runs = append([]Run(nil), s.Runs...)
+		out = append(out, s)
+	}
+	return out
+}
+
+// regroup rebuilds sessions from all known runs. When the latest run changes,
+// the latest session counts as ended if it is already idle for longer than the
+// gap, so historical sessions are never reported. Callers must hold t.mu.
+func (t *Tracker) regroup() {
+	var prevLast time.Time
+	if n := len(t.sessions); n > 0 {
+		prevLast = t.sessions[n-1].lastEnd()
+	}
+	runs := make([]Run, 0, len(t.runs))
+	for _, r := range t.runs {
+		runs = append(runs, r)
+	}
+	t.sessions = Group(runs, t.gap)
+	if n := len(t.sessions); n > 0 {
+		if last := t.sessions[n-1].lastEnd(); !last.Equal(prevLast) {
+			t.ended = time.Since(last) > t.gap
+		}
+	}
+}
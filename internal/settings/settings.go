@@ -1,13 +1,18 @@
 package settings
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"runtime"
 	"strings"
+	"time"
 
+	"refleks/internal/atomicfile"
 	"refleks/internal/constants"
 	"refleks/internal/models"
 )
@@ -36,6 +41,8 @@ func Default() models.Settings {
 		MouseTrackingEnabled: false,
 		MouseBufferMinutes:   constants.DefaultMouseBufferMinutes,
 		MaxExistingOnStart:   constants.DefaultMaxExistingOnStart,
+		APIPort:              constants.DefaultAPIPort,
+		OverlayDir:           DefaultOverlayDirString(),
 	}
 }
 
@@ -62,9 +69,99 @@ func Sanitize(s models.Settings) models.Settings {
 	if s.MaxExistingOnStart <= 0 {
 		s.MaxExistingOnStart = constants.DefaultMaxExistingOnStart
 	}
+	if strings.TrimSpace(s.OverlayDir) == "" {
+		s.OverlayDir = DefaultOverlayDirString()
+	}
+	s.TraceMaxAgeDays = max(s.TraceMaxAgeDays, 0)
+	s.TraceMaxTotalMB = max(s.TraceMaxTotalMB, 0)
+	s.TraceKeepBest = max(s.TraceKeepBest, 0)
+	if s.APIPort <= 0 || s.APIPort > 65535 {
+		s.APIPort = constants.DefaultAPIPort
+	}
+	s.APIToken = strings.TrimSpace(s.APIToken)
+	if s.APIEnabled && s.APIToken == "" {
+		s.APIToken = NewAPIToken()
+	}
 	return s
 }
 
+// NewAPIToken returns a random token for the local HTTP API.
+func NewAPIToken() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		// crypto/rand does not fail on supported platforms; keep the API locked if it does.
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
+
+// StatsSources returns the stats directories to watch: primary first, then the
+// extra sources from settings. Entries are deduplicated by cleaned path and
+// unlabeled ones get a default label (the folder name for extras).
+func StatsSources(primary models.StatsSource, extra []models.StatsSource) []models.StatsSource {
+	out := make([]models.StatsSource, 0, len(extra)+1)
+	seen := make(map[string]struct{}, len(extra)+1)
+	add := func(src models.StatsSource, defLabel string) {
+		p := strings.TrimSpace(src.Path)
+		if p == "" {
+			return
+		}
+		src.Path = filepath.Clean(ExpandPathPlaceholders(p))
+		if _, ok := seen[src.Path]; ok {
+			return
+		}
+		seen[src.Path] = struct{}{}
+		if strings.TrimSpace(src.Label) == "" {
+			src.Label = defLabel
+		}
+		out = append(out, src)
+	}
+	add(primary, constants.PrimaryStatsSourceLabel)
+	for _, src := range extra {
+		add(src, filepath.Base(filepath.Clean(strings.TrimSpace(src.Path))))
+	}
+	return out
+}
+
+// WatcherConfig builds the watcher configuration for path (the primary stats
+// directory) and the extra stats sources in s.
+func WatcherConfig(path string, s models.Settings) models.WatcherConfig {
+	// The index is an optimization; without a config dir the watcher just parses.
+	indexPath, _ := IndexPath()
+	return models.WatcherConfig{
+		Path:                 path,
+		Sources:              StatsSources(models.StatsSource{Path: path, Timezone: s.StatsTimezone}, s.StatsSources),
+		SessionGap:           time.Duration(s.SessionGapMinutes) * time.Minute,
+		PollInterval:         time.Duration(constants.DefaultPollIntervalSeconds) * time.Second,
+		ParseExistingOnStart: true,
+		ParseExistingLimit:   s.MaxExistingOnStart,
+		IndexPath:            indexPath,
+	}
+}
+
+// ValidateTimezones checks that the time zones of the stats directories are
+// known IANA names.
+func ValidateTimezones(s models.Settings) error {
+	check := func(tz, dir string) error {
+		if strings.TrimSpace(tz) == "" {
+			return nil
+		}
+		if _, err := time.LoadLocation(strings.TrimSpace(tz)); err != nil {
+			return fmt.Errorf("invalid timezone %q for %s: %w", tz, dir, err)
+		}
+		return nil
+	}
+	if err := check(s.StatsTimezone, "stats directory"); err != nil {
+		return err
+	}
+	for _, src := range s.StatsSources {
+		if err := check(src.Timezone, src.Path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // ConfigBaseDir returns the application config directory under the user's home dir: $HOME/.refleks
 func ConfigBaseDir() (string, error) {
 	home, err := os.UserHomeDir()
@@ -97,6 +194,15 @@ func DefaultTracesDir() (string, error) {
 	return filepath.Join(base, constants.TracesSubdirName), nil
 }
 
+// DefaultOverlayDirString returns the default stream overlay output directory ($HOME/.refleks/overlay).
+func DefaultOverlayDirString() string {
+	base, err := ConfigBaseDir()
+	if err != nil {
+		return filepath.ToSlash(constants.OverlaySubdirName)
+	}
+	return filepath.ToSlash(filepath.Join(base, constants.OverlaySubdirName))
+}
+
 // ExpandPathPlaceholders normalizes a path string for the current OS. No placeholders are supported.
 func ExpandPathPlaceholders(p string) string {
 	if p == "" {
@@ -115,27 +221,48 @@ func Path() (string, error) {
 	return filepath.Join(base, "settings.json"), nil
 }
 
-// Load reads settings from disk.
+// IndexPath returns the parsed-record index path under the config directory ($HOME/.refleks).
+func IndexPath() (string, error) {
+	base, err := ConfigBaseDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(base, constants.IndexFileName), nil
+}
+
+// ErrNoSettings is returned by Load when no settings file has been written yet.
+var ErrNoSettings = errors.New("no settings yet")
+
+// Load reads settings from disk, falling back to a backup when the file is
+// damaged.
 func Load() (models.Settings, error) {
+	s, _, err := LoadRecovering()
+	return s, err
+}
+
+// LoadRecovering reads settings from disk. When settings.json is missing or
+// unreadable but a backup is intact, the backup is used and its path returned
+// in recoveredFrom so the caller can report the recovery.
+func LoadRecovering() (s models.Settings, recoveredFrom string, err error) {
 	path, err := Path()
 	if err != nil {
-		return models.Settings{}, err
+		return models.Settings{}, "", err
 	}
-	b, err := os.ReadFile(path)
+	_, recoveredFrom, err = atomicfile.Read(path, func(b []byte) error {
+		s = models.Settings{}
+		return json.Unmarshal(b, &s)
+	})
 	if err != nil {
 		if errors.Is(err, os.ErrNotExist) {
-			return models.Settings{}, errors.New("no settings yet")
+			return models.Settings{}, "", ErrNoSettings
 		}
-		return models.Settings{}, err
-	}
-	var s models.Settings
-	if err := json.Unmarshal(b, &s); err != nil {
-		return models.Settings{}, err
+		return models.Settings{}, "", err
 	}
-	return s, nil
+	return s, recoveredFrom, nil
 }
 
-// Save writes settings to disk.
+// Save writes settings to disk atomically, keeping the previous versions as
+// backups.
 func Save(s models.Settings) error {
 	path, err := Path()
 	if err != nil {
@@ -145,7 +272,7 @@ func Save(s models.Settings) error {
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(path, b, 0o644)
+	return atomicfile.Write(path, b, 0o644, constants.SettingsBackups)
 }
 
 // GetFavoriteBenchmarks returns a defensive copy of the favorites list from the provided settings.
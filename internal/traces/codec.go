@@ -0,0 +1,248 @@
+package traces
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"refleks/internal/atomicfile"
+	"refleks/internal/models"
+)
+
+// Binary trace layout (all integers are varints unless noted):
+//
+//	magic "RFTR" | format version (byte) | flags (byte)
+//	body, gzip-compressed when flags&flagGzip:
+//	  version | fileName | scenarioName | datePlayed   (strings are length-prefixed)
+//	  point count n
+//	  first timestamp (unix ns), then n-1 timestamp deltas (ns)
+//	  n x deltas, n y deltas (from 0 for the first point)
+//	  button runs: pairs of (run length, buttons) covering all n points
+//
+// Storing each column separately keeps deltas of the same kind next to each
+// other, which makes them small and lets gzip find the repetition.
+const (
+	binaryMagic   = "RFTR"
+	binaryFormat  = 1
+	flagGzip      = 1 << 0
+	binaryExt     = ".rtrace"
+	legacyJSONExt = ".json"
+)
+
+var errBadTrace = errors.New("traces: malformed binary trace")
+
+// isBinary reports whether b starts with the binary trace magic.
+func isBinary(b []byte) bool {
+	return len(b) >= len(binaryMagic)+2 && string(b[:len(binaryMagic)]) == binaryMagic
+}
+
+// encodeBinary serialises sd into the binary trace format.
+func encodeBinary(sd ScenarioData, compress bool) ([]byte, error) {
+	var body []byte
+	body = binary.AppendUvarint(body, uint64(sd.Version))
+	body = appendString(body, sd.FileName)
+	body = appendString(body, sd.ScenarioName)
+	body = appendString(body, sd.DatePlayed)
+
+	pts := sd.MouseTrace
+	body = binary.AppendUvarint(body, uint64(len(pts)))
+	if len(pts) > 0 {
+		body = binary.AppendVarint(body, pts[0].TS.UnixNano())
+		for i := 1; i < len(pts); i++ {
+			body = binary.AppendVarint(body, pts[i].TS.UnixNano()-pts[i-1].TS.UnixNano())
+		}
+		var prev int32
+		for _, p := range pts {
+			body = binary.AppendVarint(body, int64(p.X)-int64(prev))
+			prev = p.X
+		}
+		prev = 0
+		for _, p := range pts {
+			body = binary.AppendVarint(body, int64(p.Y)-int64(prev))
+			prev = p.Y
+		}
+		run, cur := 0, pts[0].Buttons
+		for _, p := range pts {
+			if p.Buttons != cur {
+				body = binary.AppendUvarint(body, uint64(run))
+				body = binary.AppendUvarint(body, uint64(uint32(cur)))
+				run, cur = 0, p.Buttons
+			}
+			run++
+		}
+		body = binary.AppendUvarint(body, uint64(run))
+		body = binary.AppendUvarint(body, uint64(uint32(cur)))
+	}
+
+	var flags byte
+	if compress {
+		flags |= flagGzip
+	}
+	var out bytes.Buffer
+	out.WriteString(binaryMagic)
+	out.WriteByte(binaryFormat)
+	out.WriteByte(flags)
+	if !compress {
+		out.Write(body)
+		return out.Bytes(), nil
+	}
+	zw := gzip.NewWriter(&out)
+	if _, err := zw.Write(body); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// decodeBinary parses a file produced by encodeBinary.
+func decodeBinary(b []byte) (ScenarioData, error) {
+	r, err := openBinary(b)
+	if err != nil {
+		return ScenarioData{}, err
+	}
+	sd, n, err := readHeader(r)
+	if err != nil || n == 0 {
+		return sd, err
+	}
+	pts := make([]models.MousePoint, n)
+
+	var ts int64
+	for i := range pts {
+		d, err := binary.ReadVarint(r)
+		if err != nil {
+			return ScenarioData{}, errBadTrace
+		}
+		ts += d
+		pts[i].TS = time.Unix(0, ts)
+	}
+	var v int64
+	for i := range pts {
+		d, err := binary.ReadVarint(r)
+		if err != nil {
+			return ScenarioData{}, errBadTrace
+		}
+		v += d
+		pts[i].X = int32(v)
+	}
+	v = 0
+	for i := range pts {
+		d, err := binary.ReadVarint(r)
+		if err != nil {
+			return ScenarioData{}, errBadTrace
+		}
+		v += d
+		pts[i].Y = int32(v)
+	}
+	for i := 0; i < n; {
+		run, err := binary.ReadUvarint(r)
+		if err != nil || run == 0 || run > uint64(n-i) {
+			return ScenarioData{}, errBadTrace
+		}
+		buttons, err := binary.ReadUvarint(r)
+		if err != nil {
+			return ScenarioData{}, errBadTrace
+		}
+		for end := i + int(run); i < end; i++ {
+			pts[i].Buttons = int32(uint32(buttons))
+		}
+	}
+	sd.MouseTrace = pts
+	return sd, nil
+}
+
+// binarySpan reads the header and timestamp column of a binary trace without
+// materialising its points. It returns the point count and first/last times.
+func binarySpan(b []byte) (ScenarioData, int, time.Time, time.Time, error) {
+	r, err := openBinary(b)
+	if err != nil {
+		return ScenarioData{}, 0, time.Time{}, time.Time{}, err
+	}
+	sd, n, err := readHeader(r)
+	if err != nil || n == 0 {
+		return sd, 0, time.Time{}, time.Time{}, err
+	}
+	var ts, first int64
+	for i := 0; i < n; i++ {
+		d, err := binary.ReadVarint(r)
+		if err != nil {
+			return ScenarioData{}, 0, time.Time{}, time.Time{}, errBadTrace
+		}
+		ts += d
+		if i == 0 {
+			first = ts
+		}
+	}
+	return sd, n, time.Unix(0, first), time.Unix(0, ts), nil
+}
+
+// openBinary checks the file header and returns a reader over the
+// (decompressed) body.
+func openBinary(b []byte) (*bytes.Reader, error) {
+	if !isBinary(b) {
+		return nil, errBadTrace
+	}
+	format, flags := b[len(binaryMagic)], b[len(binaryMagic)+1]
+	if format != binaryFormat {
+		return nil, fmt.Errorf("traces: binary format %d: %w", format, atomicfile.ErrUnsupported)
+	}
+	body := b[len(binaryMagic)+2:]
+	if flags&flagGzip != 0 {
+		zr, err := gzip.NewReader(bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+		if body, err = io.ReadAll(zr); err != nil {
+			return nil, err
+		}
+	}
+	return bytes.NewReader(body), nil
+}
+
+// readHeader reads the metadata fields and the point count.
+func readHeader(r *bytes.Reader) (ScenarioData, int, error) {
+	var sd ScenarioData
+	version, err := binary.ReadUvarint(r)
+	if err != nil {
+		return ScenarioData{}, 0, errBadTrace
+	}
+	sd.Version = int(version)
+	for _, dst := range []*string{&sd.FileName, &sd.ScenarioName, &sd.DatePlayed} {
+		if *dst, err = readString(r); err != nil {
+			return ScenarioData{}, 0, err
+		}
+	}
+	count, err := binary.ReadUvarint(r)
+	if err != nil {
+		return ScenarioData{}, 0, errBadTrace
+	}
+	// Every point takes at least one byte per column, which bounds a corrupt
+	// count before allocating for it.
+	if count > uint64(r.Len()) {
+		return ScenarioData{}, 0, errBadTrace
+	}
+	return sd, int(count), nil
+}
+
+func appendString(b []byte, s string) []byte {
+	b = binary.AppendUvarint(b, uint64(len(s)))
+	return append(b, s...)
+}
+
+func readString(r *bytes.Reader) (string, error) {
+	n, err := binary.ReadUvarint(r)
+	if err != nil || n > uint64(r.Len()) {
+		return "", errBadTrace
+	}
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return "", errBadTrace
+	}
+	return string(buf), nil
+}
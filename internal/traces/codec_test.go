@@ -0,0 +1,105 @@
+package traces
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"refleks/internal/atomicfile"
+	"refleks/internal/models"
+)
+
+// sampleTrace returns a trace exercising negative deltas, repeated and
+// changing buttons and sub-millisecond timestamps.
+func sampleTrace() ScenarioData {
+	start := time.Date(2025, 10, 2, 18, 36, 37, 123456789, time.UTC)
+	sd := ScenarioData{
+		Version:      CurrentVersion,
+		FileName:     "VT 1w3ts Intermediate S5 - Challenge - 2025.10.02-18.36.37 Stats.csv",
+		ScenarioName: "VT 1w3ts Intermediate S5",
+		DatePlayed:   "2025-10-02T18:36:37Z",
+	}
+	for i := 0; i < 500; i++ {
+		sd.MouseTrace = append(sd.MouseTrace, models.MousePoint{
+			TS:      start.Add(time.Duration(i)*time.Millisecond + time.Duration(i%7)*time.Microsecond),
+			X:       int32(960 + (i%40)*3 - 60),
+			Y:       int32(540 - (i%25)*2),
+			Buttons: int32((i / 50) % 3),
+		})
+	}
+	return sd
+}
+
+func TestBinaryRoundTrip(t *testing.T) {
+	want := sampleTrace()
+	for _, compress := range []bool{false, true} {
+		b, err := encodeBinary(want, compress)
+		if err != nil {
+			t.Fatalf("encode (compress=%v): %v", compress, err)
+		}
+		got, err := decodeBinary(b)
+		if err != nil {
+			t.Fatalf("decode (compress=%v): %v", compress, err)
+		}
+		if got.Version != want.Version || got.FileName != want.FileName ||
+			got.ScenarioName != want.ScenarioName || got.DatePlayed != want.DatePlayed {
+			t.Fatalf("compress=%v: header %+v, want %+v", compress, got, want)
+		}
+		if len(got.MouseTrace) != len(want.MouseTrace) {
+			t.Fatalf("compress=%v: %d points, want %d", compress, len(got.MouseTrace), len(want.MouseTrace))
+		}
+		for i, p := range got.MouseTrace {
+			w := want.MouseTrace[i]
+			if !p.TS.Equal(w.TS) || p.X != w.X || p.Y != w.Y || p.Buttons != w.Buttons {
+				t.Fatalf("compress=%v: point %d = %+v, want %+v", compress, i, p, w)
+			}
+		}
+	}
+}
+
+func TestBinaryEmptyTrace(t *testing.T) {
+	b, err := encodeBinary(ScenarioData{Version: CurrentVersion, FileName: "x Stats.csv"}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := decodeBinary(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.FileName != "x Stats.csv" || len(got.MouseTrace) != 0 {
+		t.Fatalf("got %+v", got)
+	}
+}
+
+func TestDecodeTruncated(t *testing.T) {
+	for _, compress := range []bool{false, true} {
+		b, err := encodeBinary(sampleTrace(), compress)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for n := 0; n < len(b); n++ {
+			if _, err := decodeBinary(b[:n]); err == nil {
+				t.Fatalf("compress=%v: decoding %d of %d bytes succeeded", compress, n, len(b))
+			}
+		}
+	}
+}
+
+func TestDecodeBadHeader(t *testing.T) {
+	b, err := encodeBinary(sampleTrace(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bad := append([]byte(nil), b...)
+	copy(bad, "XXXX")
+	if _, err := decodeBinary(bad); !errors.Is(err, errBadTrace) {
+		t.Fatalf("bad magic: err = %v, want errBadTrace", err)
+	}
+
+	newer := append([]byte(nil), b...)
+	newer[len(binaryMagic)] = binaryFormat + 1
+	if _, err := decodeBinary(newer); !errors.Is(err, atomicfile.ErrUnsupported) {
+		t.Fatalf("newer format: err = %v, want ErrUnsupported", err)
+	}
+}
@@ -0,0 +1,169 @@
+package traces
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"refleks/internal/atomicfile"
+	"refleks/internal/models"
+)
+
+// Policy holds the trace retention rules. Zero values disable a rule.
+type Policy struct {
+	// MaxAge deletes traces of runs played longer ago than this.
+	MaxAge time.Duration
+	// MaxTotalBytes deletes the oldest traces until the store fits.
+	MaxTotalBytes int64
+	// KeepBestPerScenario keeps only the N highest-scoring traced runs of each scenario.
+	KeepBestPerScenario int
+	// DeleteOrphans deletes traces whose stats file no longer exists.
+	DeleteOrphans bool
+}
+
+// Run describes the stats file a trace may belong to. ScenarioName is empty
+// when the run has not been parsed yet, which exempts it from KeepBestPerScenario.
+type Run struct {
+	FileName     string
+	ScenarioName string
+	Score        float64
+	PlayedAt     time.Time
+}
+
+// storedTrace is a trace file found in the traces directory.
+type storedTrace struct {
+	path string
+	size int64
+	when time.Time
+	run  *Run
+}
+
+// GC applies p to the traces directory and reports what it deleted. runs must
+// list every stats file that still exists; pass nil when that is unknown, which
+// skips DeleteOrphans and KeepBestPerScenario. Quarantined ".corrupt" files are
+// always removed; rules then apply in the order orphans, age, best-N, then
+// total size (oldest first).
+func GC(p Policy, runs []Run, now time.Time) (models.TraceGCReport, error) {
+	var rep models.TraceGCReport
+	dir, err := tracesDir()
+	if err != nil {
+		return rep, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return rep, err
+	}
+	byStem := make(map[string]*Run, len(runs))
+	for i := range runs {
+		byStem[stemFor(runs[i].FileName)] = &runs[i]
+	}
+
+	var all []*storedTrace
+	for _, e := range entries {
+		name := e.Name()
+		// Damaged traces set aside when a backup was loaded instead.
+		if !e.IsDir() && strings.HasSuffix(name, atomicfile.CorruptSuffix) {
+			if fi, err := e.Info(); err == nil && os.Remove(filepath.Join(dir, name)) == nil {
+				rep.Corrupt++
+				rep.Deleted++
+				rep.FreedBytes += fi.Size()
+			}
+			continue
+		}
+		ext := strings.ToLower(filepath.Ext(name))
+		if e.IsDir() || (ext != binaryExt && ext != legacyJSONExt) {
+			continue
+		}
+		fi, err := e.Info()
+		if err != nil {
+			continue
+		}
+		t := &storedTrace{path: filepath.Join(dir, name), size: fi.Size(), when: fi.ModTime()}
+		if r, ok := byStem[name[:len(name)-len(ext)]]; ok {
+			t.run = r
+			if !r.PlayedAt.IsZero() {
+				t.when = r.PlayedAt
+			}
+		}
+		all = append(all, t)
+	}
+
+	deleted := make(map[*storedTrace]bool)
+	// failed keeps a trace that could not be deleted from being retried (and
+	// counted again) by a later rule.
+	failed := make(map[*storedTrace]bool)
+	remove := func(t *storedTrace, counter *int) {
+		if deleted[t] || failed[t] {
+			return
+		}
+		if err := atomicfile.Remove(t.path); err != nil && !os.IsNotExist(err) {
+			failed[t] = true
+			rep.Failed++
+			return
+		}
+		deleted[t] = true
+		*counter++
+		rep.Deleted++
+		rep.FreedBytes += t.size
+	}
+
+	if p.DeleteOrphans && runs != nil {
+		for _, t := range all {
+			if t.run == nil {
+				remove(t, &rep.Orphaned)
+			}
+		}
+	}
+	if p.MaxAge > 0 {
+		cutoff := now.Add(-p.MaxAge)
+		for _, t := range all {
+			if t.when.Before(cutoff) {
+				remove(t, &rep.Expired)
+			}
+		}
+	}
+	if p.KeepBestPerScenario > 0 && runs != nil {
+		byScenario := make(map[string][]*storedTrace)
+		for _, t := range all {
+			if !deleted[t] && t.run != nil && t.run.ScenarioName != "" {
+				byScenario[t.run.ScenarioName] = append(byScenario[t.run.ScenarioName], t)
+			}
+		}
+		for _, ts := range byScenario {
+			sort.SliceStable(ts, func(i, j int) bool { return ts[i].run.Score > ts[j].run.Score })
+			for _, t := range ts[min(p.KeepBestPerScenario, len(ts)):] {
+				remove(t, &rep.BeyondBest)
+			}
+		}
+	}
+	if p.MaxTotalBytes > 0 {
+		var left []*storedTrace
+		var total int64
+		for _, t := range all {
+			if !deleted[t] {
+				left = append(left, t)
+				total += t.size
+			}
+		}
+		sort.SliceStable(left, func(i, j int) bool { return left[i].when.Before(left[j].when) })
+		for _, t := range left {
+			if total <= p.MaxTotalBytes {
+				break
+			}
+			remove(t, &rep.OverBudget)
+			if deleted[t] {
+				total -= t.size
+			}
+		}
+	}
+
+	for _, t := range all {
+		if !deleted[t] {
+			rep.Remaining++
+			rep.RemainingBytes += t.size
+		}
+	}
+	return rep, nil
+}
@@ -0,0 +1,145 @@
+package traces
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"refleks/internal/atomicfile"
+	"refleks/internal/models"
+)
+
+// List returns metadata of every stored trace, most recently played first.
+// Traces that cannot be read are listed with Error set so they can be deleted.
+func List() ([]models.TraceInfo, error) {
+	dir, err := tracesDir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]models.TraceInfo, 0, len(entries))
+	for _, e := range entries {
+		name := e.Name()
+		ext := strings.ToLower(filepath.Ext(name))
+		if e.IsDir() || (ext != binaryExt && ext != legacyJSONExt) {
+			continue
+		}
+		fi, err := e.Info()
+		if err != nil {
+			continue
+		}
+		out = append(out, inspect(filepath.Join(dir, name), fi))
+	}
+	sort.SliceStable(out, func(i, j int) bool { return playedTime(out[i].DatePlayed).After(playedTime(out[j].DatePlayed)) })
+	return out, nil
+}
+
+// inspect reads the metadata of one trace file.
+func inspect(path string, fi os.FileInfo) models.TraceInfo {
+	info := models.TraceInfo{
+		TraceFile:  fi.Name(),
+		FileName:   fi.Name(),
+		Bytes:      fi.Size(),
+		DatePlayed: fi.ModTime().UTC().Format(time.RFC3339),
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		info.Error = err.Error()
+		return info
+	}
+	var sd ScenarioData
+	var first, last time.Time
+	if isBinary(b) {
+		sd, info.Points, first, last, err = binarySpan(b)
+	} else if err = json.Unmarshal(b, &sd); err == nil {
+		info.Points = len(sd.MouseTrace)
+		if info.Points > 0 {
+			first, last = sd.MouseTrace[0].TS, sd.MouseTrace[info.Points-1].TS
+		}
+	}
+	if err != nil {
+		info.Error = err.Error()
+		return info
+	}
+	info.Version = sd.Version
+	info.Legacy = !isBinary(b)
+	if sd.FileName != "" {
+		info.FileName = sd.FileName
+	}
+	info.ScenarioName = sd.ScenarioName
+	if sd.DatePlayed != "" {
+		info.DatePlayed = sd.DatePlayed
+	}
+	if info.Points > 1 {
+		info.DurationMs = last.Sub(first).Milliseconds()
+	}
+	return info
+}
+
+// Delete removes the stored trace of the given stats (or trace) file name,
+// including its backups and a legacy JSON copy. It returns os.ErrNotExist if there was none.
+func Delete(fileName string) error {
+	removed := false
+	for _, ext := range []string{binaryExt, legacyJSONExt} {
+		path, err := pathFor(fileName, ext)
+		if err != nil {
+			return err
+		}
+		if err := atomicfile.Remove(path); err == nil {
+			removed = true
+		} else if !os.IsNotExist(err) {
+			return err
+		}
+	}
+	if !removed {
+		return os.ErrNotExist
+	}
+	return nil
+}
+
+// Stats aggregates the trace store.
+func Stats() (models.TraceStats, error) {
+	list, err := List()
+	if err != nil {
+		return models.TraceStats{}, err
+	}
+	var st models.TraceStats
+	scenarios := make(map[string]struct{})
+	for _, t := range list {
+		st.Count++
+		st.TotalBytes += t.Bytes
+		st.TotalPoints += int64(t.Points)
+		st.TotalDurationMs += t.DurationMs
+		switch {
+		case t.Error != "":
+			st.Unreadable++
+		case t.Legacy:
+			st.Legacy++
+		}
+		if t.ScenarioName != "" {
+			scenarios[t.ScenarioName] = struct{}{}
+		}
+		if t.Error == "" {
+			if st.Oldest == "" || playedTime(t.DatePlayed).Before(playedTime(st.Oldest)) {
+				st.Oldest = t.DatePlayed
+			}
+			if st.Newest == "" || playedTime(t.DatePlayed).After(playedTime(st.Newest)) {
+				st.Newest = t.DatePlayed
+			}
+		}
+	}
+	st.Scenarios = len(scenarios)
+	return st, nil
+}
+
+// playedTime parses an RFC3339 DatePlayed; unparsable values sort last.
+func playedTime(s string) time.Time {
+	v, _ := time.Parse(time.RFC3339, s)
+	return v
+}
@@ -0,0 +1,111 @@
+package traces
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"refleks/internal/atomicfile"
+	"refleks/internal/models"
+)
+
+// VersionError is returned for data written by a newer version of the app.
+// Such files are left untouched so a downgrade does not destroy them.
+type VersionError struct {
+	FileName  string
+	Version   int
+	Supported int
+}
+
+// Unwrap lets atomicfile.Read tell newer data from damaged data.
+func (e *VersionError) Unwrap() error { return atomicfile.ErrUnsupported }
+
+func (e *VersionError) Error() string {
+	return fmt.Sprintf("traces: %s has data version %d, this app supports up to %d; update refleks to read it", e.FileName, e.Version, e.Supported)
+}
+
+// upgrades maps a data version to the function that upgrades it to the next
+// version in place. Add an entry (and bump CurrentVersion) whenever the
+// meaning or shape of ScenarioData changes.
+var upgrades = map[int]func(*ScenarioData) error{}
+
+// migrate brings sd up to CurrentVersion. Data without a version predates the
+// field and is treated as version 1.
+func migrate(sd *ScenarioData) error {
+	if sd.Version <= 0 {
+		sd.Version = 1
+	}
+	if sd.Version > CurrentVersion {
+		return &VersionError{FileName: sd.FileName, Version: sd.Version, Supported: CurrentVersion}
+	}
+	for sd.Version < CurrentVersion {
+		up, ok := upgrades[sd.Version]
+		if !ok {
+			return fmt.Errorf("traces: no upgrade from data version %d", sd.Version)
+		}
+		if err := up(sd); err != nil {
+			return fmt.Errorf("traces: upgrading %s from version %d: %w", sd.FileName, sd.Version, err)
+		}
+		sd.Version++
+	}
+	return nil
+}
+
+// MigrateAll rewrites every stored trace that is older than CurrentVersion or
+// not in the current binary format. Loading upgrades data on the fly anyway; this makes
+// the upgrade permanent and converts legacy files to the compact format.
+func MigrateAll() (models.TraceMigrationReport, error) {
+	var rep models.TraceMigrationReport
+	dir, err := tracesDir()
+	if err != nil {
+		return rep, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return rep, err
+	}
+	for _, e := range entries {
+		name := e.Name()
+		ext := strings.ToLower(filepath.Ext(name))
+		if e.IsDir() || (ext != binaryExt && ext != legacyJSONExt) {
+			continue
+		}
+		rep.Checked++
+		b, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			rep.Failed++
+			continue
+		}
+		sd, err := decode(b)
+		if errors.Is(err, atomicfile.ErrUnsupported) {
+			rep.Newer++
+			continue
+		}
+		if err != nil {
+			rep.Failed++
+			continue
+		}
+		// decode only accepts the current binaryFormat.
+		if sd.Version == CurrentVersion && isBinary(b) {
+			continue
+		}
+		if err := migrate(&sd); err != nil {
+			var verr *VersionError
+			if errors.As(err, &verr) {
+				rep.Newer++
+			} else {
+				rep.Failed++
+			}
+			continue
+		}
+		// Keep the on-disk name, which may differ from the recorded FileName.
+		if err := save(name, sd); err != nil {
+			rep.Failed++
+			continue
+		}
+		rep.Upgraded++
+	}
+	return rep, nil
+}
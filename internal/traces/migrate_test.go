@@ -0,0 +1,106 @@
+package traces
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const legacyFixture = "VT 1w3ts Intermediate S5 - Challenge - 2025.11.02-10.53.10.json"
+
+// useTempStore points the trace store at an empty temporary directory.
+func useTempStore(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	SetBaseDir(dir)
+	t.Cleanup(func() { SetBaseDir("") })
+	return dir
+}
+
+func TestMigrateLegacyJSON(t *testing.T) {
+	dir := useTempStore(t)
+	b, err := os.ReadFile(filepath.Join("..", "..", "testdata", "traces", legacyFixture))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, legacyFixture), b, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	statsName := "VT 1w3ts Intermediate S5 - Challenge - 2025.11.02-10.53.10 Stats.csv"
+
+	before, err := Load(statsName)
+	if err != nil {
+		t.Fatalf("Load v1 JSON: %v", err)
+	}
+	if before.Version != CurrentVersion || len(before.MouseTrace) == 0 {
+		t.Fatalf("loaded version %d with %d points", before.Version, len(before.MouseTrace))
+	}
+
+	rep, err := MigrateAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rep.Checked != 1 || rep.Upgraded != 1 || rep.Failed != 0 {
+		t.Fatalf("report %+v, want one upgraded trace", rep)
+	}
+	if _, err := os.Stat(filepath.Join(dir, legacyFixture)); !os.IsNotExist(err) {
+		t.Fatalf("legacy JSON still present: %v", err)
+	}
+	b, err = os.ReadFile(filepath.Join(dir, stemFor(statsName)+binaryExt))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !isBinary(b) {
+		t.Fatal("migrated trace is not binary")
+	}
+
+	after, err := Load(statsName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(after.MouseTrace) != len(before.MouseTrace) || !after.MouseTrace[0].TS.Equal(before.MouseTrace[0].TS) {
+		t.Fatalf("migrated trace differs: %d points, want %d", len(after.MouseTrace), len(before.MouseTrace))
+	}
+
+	rep, err = MigrateAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rep.Checked != 1 || rep.Upgraded != 0 {
+		t.Fatalf("second pass %+v, want nothing to upgrade", rep)
+	}
+}
+
+func TestNewerVersionRejected(t *testing.T) {
+	dir := useTempStore(t)
+	sd := sampleTrace()
+	sd.Version = CurrentVersion + 1
+	b, err := encodeBinary(sd, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, stemFor(sd.FileName)+binaryExt)
+	if err := os.WriteFile(path, b, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = Load(sd.FileName)
+	var verr *VersionError
+	if !errors.As(err, &verr) || verr.Version != CurrentVersion+1 {
+		t.Fatalf("Load: err = %v, want *VersionError for version %d", err, CurrentVersion+1)
+	}
+	// The file must be left alone so a newer app can still read it.
+	got, err := os.ReadFile(path)
+	if err != nil || string(got) != string(b) {
+		t.Fatalf("newer trace was modified or moved: %v", err)
+	}
+
+	rep, err := MigrateAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rep.Newer != 1 || rep.Upgraded != 0 {
+		t.Fatalf("report %+v, want one newer trace", rep)
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"path/filepath"
 	"strings"
 
+	"refleks/internal/atomicfile"
+	"refleks/internal/constants"
 	"refleks/internal/models"
 	appsettings "refleks/internal/settings"
 )
@@ -48,7 +50,7 @@ func tracesDir() (string, error) {
 	return dir, nil
 }
 
-// sanitizeName converts an arbitrary filename into a safe file stem for JSON storage.
+// sanitizeName converts an arbitrary filename into a safe file stem for storage.
 func sanitizeName(name string) string {
 	// Keep base name only and replace any path separators with underscores.
 	base := filepath.Base(name)
@@ -57,48 +59,135 @@ func sanitizeName(name string) string {
 	return base
 }
 
-// pathFor returns the full JSON path for the given scenario file name.
-func pathFor(fileName string) (string, error) {
+// CurrentVersion is the ScenarioData version written by Save. It tracks the
+// meaning of the fields, not the file encoding (see binaryFormat); bump it
+// together with upgrades in migrate.go.
+const CurrentVersion = 1
+
+// compress controls whether Save gzips the binary encoding.
+var compress = true
+
+// onRecover is called when Load had to fall back to a backup.
+var onRecover func(fileName, backup string)
+
+// SetRecoveryHandler registers fn to be told when a damaged trace was loaded
+// from its backup instead.
+func SetRecoveryHandler(fn func(fileName, backup string)) {
+	onRecover = fn
+}
+
+// SetCompression turns gzip compression of newly saved traces on or off.
+// Load reads both compressed and uncompressed files.
+func SetCompression(on bool) {
+	compress = on
+}
+
+// stemFor returns the file name stem (without extension) for a stats file name.
+func stemFor(fileName string) string {
+	stem := sanitizeName(fileName)
+	lower := strings.ToLower(stem)
+	switch {
+	case strings.HasSuffix(lower, " stats.csv"):
+		stem = stem[:len(stem)-len(" stats.csv")]
+	case strings.HasSuffix(lower, ".csv"):
+		stem = stem[:len(stem)-len(".csv")]
+	case strings.HasSuffix(lower, binaryExt):
+		stem = stem[:len(stem)-len(binaryExt)]
+	case strings.HasSuffix(lower, legacyJSONExt):
+		stem = stem[:len(stem)-len(legacyJSONExt)]
+	}
+	return stem
+}
+
+// pathFor returns the full path with the given extension for a scenario file name.
+func pathFor(fileName, ext string) (string, error) {
 	dir, err := tracesDir()
 	if err != nil {
 		return "", err
 	}
-	stem := sanitizeName(fileName)
-	// Replace common suffix, else just append .json
-	if strings.HasSuffix(strings.ToLower(stem), " stats.csv") {
-		stem = stem[:len(stem)-len(" stats.csv")] + ".json"
-	} else if strings.HasSuffix(strings.ToLower(stem), ".csv") {
-		stem = stem[:len(stem)-4] + ".json"
-	} else if !strings.HasSuffix(strings.ToLower(stem), ".json") {
-		stem = stem + ".json"
-	}
-	return filepath.Join(dir, stem), nil
+	return filepath.Join(dir, stemFor(fileName)+ext), nil
+}
+
+// existingPath returns the path of the stored trace for fileName, preferring
+// the binary file over a legacy JSON one.
+func existingPath(fileName string) (string, error) {
+	for _, ext := range []string{binaryExt, legacyJSONExt} {
+		path, err := pathFor(fileName, ext)
+		if err != nil {
+			return "", err
+		}
+		if fi, err := os.Stat(path); err == nil && !fi.IsDir() {
+			return path, nil
+		}
+	}
+	return "", os.ErrNotExist
 }
 
-// Save writes scenario data to disk (overwriting if exists).
+// Save writes scenario data to disk atomically (overwriting if exists) in the
+// binary format, keeping the previous file as a backup and removing a legacy
+// JSON copy of the same trace.
 func Save(sd ScenarioData) error {
-	path, err := pathFor(sd.FileName)
+	return save(sd.FileName, sd)
+}
+
+// save writes sd to the trace file named after key.
+func save(key string, sd ScenarioData) error {
+	path, err := pathFor(key, binaryExt)
 	if err != nil {
 		return err
 	}
-	sd.Version = 1
-	b, err := json.MarshalIndent(sd, "", "  ")
+	sd.Version = CurrentVersion
+	b, err := encodeBinary(sd, compress)
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(path, b, 0o644)
+	if err := atomicfile.Write(path, b, 0o644, constants.TraceBackups); err != nil {
+		return err
+	}
+	if legacy, err := pathFor(key, legacyJSONExt); err == nil {
+		_ = atomicfile.Remove(legacy)
+	}
+	return nil
 }
 
-// Load reads scenario data for the given stats file name.
+// Load reads scenario data for the given stats file name. Both the binary
+// format and version 1 JSON files are accepted, and older data is upgraded to
+// CurrentVersion. Data from a newer app version fails with a *VersionError.
 func Load(fileName string) (ScenarioData, error) {
-	path, err := pathFor(fileName)
+	path, err := existingPath(fileName)
 	if err != nil {
-		return ScenarioData{}, err
+		// A crash mid-save can leave only the backup of the binary file.
+		if path, err = pathFor(fileName, binaryExt); err != nil {
+			return ScenarioData{}, err
+		}
 	}
-	b, err := os.ReadFile(path)
+	var sd ScenarioData
+	_, from, err := atomicfile.Read(path, func(b []byte) (err error) {
+		if sd, err = decode(b); err == nil && sd.Version > CurrentVersion {
+			err = &VersionError{FileName: fileName, Version: sd.Version, Supported: CurrentVersion}
+		}
+		return err
+	})
 	if err != nil {
 		return ScenarioData{}, err
 	}
+	if from != "" && onRecover != nil {
+		onRecover(fileName, from)
+	}
+	if sd.FileName == "" {
+		sd.FileName = fileName
+	}
+	if err := migrate(&sd); err != nil {
+		return ScenarioData{}, err
+	}
+	return sd, nil
+}
+
+// decode parses either encoding based on the file contents.
+func decode(b []byte) (ScenarioData, error) {
+	if isBinary(b) {
+		return decodeBinary(b)
+	}
 	var sd ScenarioData
 	if err := json.Unmarshal(b, &sd); err != nil {
 		return ScenarioData{}, err
@@ -108,12 +197,6 @@ func Load(fileName string) (ScenarioData, error) {
 
 // Exists reports whether a persisted record exists for the given stats file name.
 func Exists(fileName string) bool {
-	path, err := pathFor(fileName)
-	if err != nil {
-		return false
-	}
-	if fi, err := os.Stat(path); err == nil && !fi.IsDir() {
-		return true
-	}
-	return false
+	_, err := existingPath(fileName)
+	return err == nil
 }
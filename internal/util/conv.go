@@ -1,34 +0,0 @@
-package util
-
-import (
-	"strconv"
-	"strings"
-)
-
-// ToFloat attempts to coerce various numeric representations to float64.
-// Supports int types, float types, and decimal strings. Non-numeric values return 0.
-func ToFloat(v any) float64 {
-	switch t := v.(type) {
-	case int:
-		return float64(t)
-	case int32:
-		return float64(t)
-	case int64:
-		return float64(t)
-	case uint:
-		return float64(t)
-	case uint32:
-		return float64(t)
-	case uint64:
-		return float64(t)
-	case float32:
-		return float64(t)
-	case float64:
-		return t
-	case string:
-		if f, err := strconv.ParseFloat(strings.TrimSpace(t), 64); err == nil {
-			return f
-		}
-	}
-	return 0
-}
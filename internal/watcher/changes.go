@@ -0,0 +1,164 @@
+package watcher
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"refleks/internal/events"
+	"refleks/internal/models"
+	"refleks/internal/query"
+)
+
+// fileState is what the watcher last knew about a stats file. Size and mtime
+// are cheap to compare on every scan; the content hash tells a real rewrite
+// apart from a touch and recognizes renamed files.
+type fileState struct {
+	size    int64
+	modTime time.Time
+	// hash is empty when the file was never parsed successfully.
+	hash string
+	// archived marks entries imported from a backup archive; they are not on
+	// disk and never checked for changes.
+	archived bool
+}
+
+// changed reports whether f differs from the recorded state.
+func (s fileState) changed(f statsFile) bool {
+	return !s.archived && (s.size != f.size || !s.modTime.Equal(f.modTime))
+}
+
+// recordChange describes how an ingested file affected the recent list.
+type recordChange int
+
+const (
+	changeNone    recordChange = iota
+	changeAdded                // new run appended to recent
+	changeUpdated              // existing run re-parsed in place
+	changeMoved                // existing run now lives at a new path
+)
+
+// recentIndex returns the index of the record for path in recent, or -1.
+// Callers must hold w.mu.
+func (w *Watcher) recentIndex(path string) int {
+	for i := len(w.recent) - 1; i >= 0; i-- {
+		if w.recent[i].FilePath == path {
+			return i
+		}
+	}
+	return -1
+}
+
+// moveRecord points the record stored for from at rec (ingested from a new
+// path), keeping its position in recent and any trace already attached.
+// It reports whether a record was found. Callers must hold w.mu.
+func (w *Watcher) moveRecord(from string, rec *models.ScenarioRecord) bool {
+	delete(w.seen, from)
+	delete(w.partial, from)
+	if w.index != nil {
+		w.index.Delete(from)
+	}
+	i := w.recentIndex(from)
+	if i < 0 {
+		return false
+	}
+	if len(rec.MouseTrace) == 0 {
+		rec.MouseTrace = w.recent[i].MouseTrace
+	}
+	rec.HasTrace = rec.HasTrace || w.recent[i].HasTrace
+	w.recent[i] = *rec
+	return true
+}
+
+// removeFile forgets a stats file that disappeared from disk and emits
+// "ScenarioRemoved" for its run. When another watched copy of the same content
+// still exists, the run is moved to that copy instead of being dropped.
+func (w *Watcher) removeFile(full string) {
+	w.mu.Lock()
+	st, ok := w.seen[full]
+	if !ok || st.archived {
+		w.mu.Unlock()
+		return
+	}
+	delete(w.seen, full)
+	delete(w.partial, full)
+	if w.index != nil {
+		w.index.Delete(full)
+	}
+	if st.hash != "" && w.byHash[st.hash] == full {
+		delete(w.byHash, st.hash)
+	}
+	i := w.recentIndex(full)
+	if i < 0 {
+		w.mu.Unlock()
+		w.scheduleIndexSave()
+		return
+	}
+	rec := w.recent[i]
+	moved := false
+	if copyPath, found := w.findCopy(st.hash, full); found {
+		rec.FilePath = copyPath
+		rec.FileName = filepath.Base(copyPath)
+		if src, ok := w.sourceFor(copyPath); ok {
+			rec.Source = src.Label
+		}
+		w.recent[i] = rec
+		w.byHash[st.hash] = copyPath
+		moved = true
+	} else {
+		w.recent = append(w.recent[:i], w.recent[i+1:]...)
+	}
+	w.mu.Unlock()
+
+	if moved {
+		w.sessions.Replace(full, runOf(rec))
+	} else {
+		w.sessions.Remove(full)
+	}
+	w.scheduleIndexSave()
+	w.sink.Logf(events.Info, "stats file removed: %s", full)
+	w.sink.Emit("ScenarioRemoved", models.ScenarioRemoved{FilePath: full, FileName: filepath.Base(full)})
+	if moved {
+		w.sink.Emit("ScenarioUpdated", query.Summarize(rec))
+	}
+}
+
+// findCopy returns another existing file with the given content hash.
+// Callers must hold w.mu.
+func (w *Watcher) findCopy(hash, except string) (string, bool) {
+	if hash == "" {
+		return "", false
+	}
+	for p, st := range w.seen {
+		if p == except || st.archived || st.hash != hash {
+			continue
+		}
+		if _, err := os.Stat(p); err == nil {
+			return p, true
+		}
+	}
+	return "", false
+}
+
+// removeMissing removes every known file of the listed sources that is no
+// longer present. Files of sources that could not be listed are kept, so a
+// temporarily unreachable folder does not wipe its runs.
+func (w *Watcher) removeMissing(listed map[string]bool, present map[string]struct{}) {
+	var gone []string
+	w.mu.RLock()
+	for p, st := range w.seen {
+		if st.archived {
+			continue
+		}
+		if _, ok := present[p]; ok {
+			continue
+		}
+		if src, ok := w.sourceFor(p); ok && listed[src.Path] {
+			gone = append(gone, p)
+		}
+	}
+	w.mu.RUnlock()
+	for _, p := range gone {
+		w.removeFile(p)
+	}
+}
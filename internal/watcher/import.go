@@ -0,0 +1,181 @@
+package watcher
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"refleks/internal/constants"
+	"refleks/internal/events"
+	"refleks/internal/importer"
+	"refleks/internal/models"
+	"refleks/internal/parser"
+	"refleks/internal/query"
+)
+
+// ImportArchive ingests every stats CSV from a .zip or .tar(.gz) backup of a
+// Kovaak's stats folder into the stats source whose path or label is source
+// (the primary source when empty); its time zone is used for the file names.
+// Runs whose content is already known or on disk in that source are skipped,
+// and imported runs are kept in the record index so they survive a restart.
+// Progress is emitted as "ImportProgress" events; the final state is returned.
+func (w *Watcher) ImportArchive(archivePath, source string) (models.ImportProgress, error) {
+	prog := models.ImportProgress{Archive: archivePath}
+	target, ok := w.sourceNamed(source)
+	if !ok {
+		return prog, fmt.Errorf("unknown stats source %q", source)
+	}
+	loc := sourceLocation(target)
+	var imported []models.ScenarioRecord
+	// hashes covers the archive itself, which may hold the same run twice.
+	hashes := make(map[string]struct{})
+
+	err := importer.WalkStats(archivePath, func(name string, r io.Reader) error {
+		prog.Processed++
+		if prog.Processed%constants.ImportProgressEvery == 0 {
+			w.sink.Emit("ImportProgress", prog)
+		}
+		base := path.Base(name)
+		info, err := parser.ParseFilenameIn(base, loc)
+		if err != nil {
+			prog.Failed++
+			return nil
+		}
+		b, err := io.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		hash := contentHash(b)
+		if _, dup := hashes[hash]; dup || w.knownHash(hash) || w.onDisk(target, name, hash) {
+			prog.Duplicates++
+			return nil
+		}
+		sf, err := parser.Parse(bytes.NewReader(b), base)
+		if err != nil {
+			w.sink.Logf(events.Warning, "import: skipping %s: %v", name, err)
+			prog.Failed++
+			return nil
+		}
+		hashes[hash] = struct{}{}
+		rec := w.buildRecord(importer.EntryPath(archivePath, name), info, sf, false)
+		rec.Source = filepath.Base(archivePath)
+		rec.ContentHash = hash
+		imported = append(imported, rec)
+		prog.Imported++
+		return nil
+	})
+
+	w.mergeRecent(imported)
+	w.sessions.AddAll(runsOf(imported))
+	w.saveIndex()
+	prog.Done = true
+	w.sink.Emit("ImportProgress", prog)
+	w.sink.Logf(events.Info, "import %s: %d imported, %d duplicates, %d failed", archivePath, prog.Imported, prog.Duplicates, prog.Failed)
+	return prog, err
+}
+
+// knownHash reports whether a run with the given content hash was ingested.
+func (w *Watcher) knownHash(hash string) bool {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	_, ok := w.byHash[hash]
+	return ok
+}
+
+// sourceNamed returns the configured source whose path or label is name, or
+// the primary source when name is empty.
+func (w *Watcher) sourceNamed(name string) (models.StatsSource, bool) {
+	srcs := w.sources()
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return srcs[0], true
+	}
+	for _, src := range srcs {
+		if filepath.Clean(src.Path) == filepath.Clean(name) || (src.Label != "" && src.Label == name) {
+			return src, true
+		}
+	}
+	return models.StatsSource{}, false
+}
+
+// onDisk reports whether the archive entry name is a copy of a stats file in
+// src: the file of the same name there (or at the entry's relative path in a
+// recursive source) has the same content. Files not hashed yet, such as those
+// beyond ParseExistingLimit before the backfill, are read to compare.
+func (w *Watcher) onDisk(src models.StatsSource, name, hash string) bool {
+	paths := []string{filepath.Join(src.Path, path.Base(name))}
+	if rel := filepath.FromSlash(name); src.Recursive && filepath.IsLocal(rel) {
+		paths = append(paths, filepath.Join(src.Path, rel))
+	}
+	for _, p := range paths {
+		w.mu.RLock()
+		st := w.seen[p]
+		w.mu.RUnlock()
+		if st.hash == "" {
+			b, err := os.ReadFile(p)
+			if err != nil {
+				continue
+			}
+			st.hash = contentHash(b)
+		}
+		if st.hash == hash {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeRecent inserts imported records into the recent list, keeping it
+// ordered oldest-first and bounded by the recent cap, and stores them in the
+// record index.
+func (w *Watcher) mergeRecent(recs []models.ScenarioRecord) {
+	if len(recs) == 0 {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, rec := range recs {
+		w.seen[rec.FilePath] = fileState{hash: rec.ContentHash, archived: true}
+		if _, dup := w.byHash[rec.ContentHash]; !dup {
+			w.byHash[rec.ContentHash] = rec.FilePath
+		}
+		if w.index != nil {
+			w.index.PutArchived(rec.FilePath, rec)
+		}
+	}
+	w.insertRecent(recs)
+}
+
+// loadArchived restores the runs imported earlier from the record index and
+// returns them oldest-first.
+func (w *Watcher) loadArchived() []models.ScenarioRecord {
+	if w.index == nil {
+		return nil
+	}
+	recs := w.index.Archived()
+	var kept []models.ScenarioRecord
+	w.mu.Lock()
+	for _, rec := range recs {
+		if _, dup := w.byHash[rec.ContentHash]; dup {
+			continue
+		}
+		w.seen[rec.FilePath] = fileState{hash: rec.ContentHash, archived: true}
+		w.byHash[rec.ContentHash] = rec.FilePath
+		kept = append(kept, rec)
+	}
+	sort.SliceStable(kept, func(i, j int) bool { return recordTime(kept[i]).Before(recordTime(kept[j])) })
+	w.insertRecent(kept)
+	w.mu.Unlock()
+	return kept
+}
+
+// recordTime returns the run end time of a record.
+func recordTime(rec models.ScenarioRecord) time.Time {
+	return query.DatePlayed(rec)
+}
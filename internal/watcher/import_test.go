@@ -0,0 +1,93 @@
+package watcher
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"refleks/internal/events"
+	"refleks/internal/models"
+	"refleks/internal/parser"
+)
+
+// zipStats writes a zip archive of the named stats fixtures under stats/.
+func zipStats(t *testing.T, names []string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "backup.zip")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	zw := zip.NewWriter(f)
+	for _, name := range names {
+		b, err := os.ReadFile(filepath.Join(statsFixtures, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		e, err := zw.Create("stats/" + name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := e.Write(b); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestImportSkipsUnhashedFilesOnDisk(t *testing.T) {
+	dir, names := copyStats(t, 5)
+	t.Setenv("HOME", t.TempDir())
+	// Without an index the runs beyond the limit are seen but never parsed.
+	w := New(events.NewRecorder(), models.WatcherConfig{Path: dir, ParseExistingLimit: 2})
+	if err := w.Scan(); err != nil {
+		t.Fatal(err)
+	}
+
+	prog, err := w.ImportArchive(zipStats(t, names), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if prog.Imported != 0 || prog.Duplicates != len(names) {
+		t.Fatalf("progress %+v, want every run skipped as a duplicate", prog)
+	}
+}
+
+func TestImportUsesTargetSourceTimezone(t *testing.T) {
+	loc, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skip("no time zone data:", err)
+	}
+	_, names := copyStats(t, 1)
+	t.Setenv("HOME", t.TempDir())
+	w := New(events.NewRecorder(), models.WatcherConfig{Sources: []models.StatsSource{
+		{Path: t.TempDir(), Label: "main"},
+		{Path: t.TempDir(), Label: "laptop", Timezone: "Asia/Tokyo"},
+	}})
+	archive := zipStats(t, names)
+
+	if _, err := w.ImportArchive(archive, "desktop"); err == nil {
+		t.Fatal("import into an unknown source succeeded")
+	}
+	prog, err := w.ImportArchive(archive, "laptop")
+	if err != nil || prog.Imported != 1 {
+		t.Fatalf("import: %+v, %v", prog, err)
+	}
+	recent := w.GetRecent(0)
+	if len(recent) != 1 {
+		t.Fatalf("recent holds %d runs, want 1", len(recent))
+	}
+	info, err := parser.ParseFilenameIn(names[0], loc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := info.DatePlayed.UTC().Format(time.RFC3339); recent[0].PlayedAt != want {
+		t.Fatalf("PlayedAt = %s, want %s", recent[0].PlayedAt, want)
+	}
+}
@@ -0,0 +1,193 @@
+package watcher
+
+import (
+	"sort"
+	"time"
+
+	"refleks/internal/constants"
+	"refleks/internal/events"
+	"refleks/internal/index"
+	"refleks/internal/models"
+	"refleks/internal/traces"
+)
+
+// openIndex loads the record index configured in cfg, keeping the one already
+// open when the path did not change.
+func (w *Watcher) openIndex() {
+	path := w.cfg.IndexPath
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if path == "" {
+		w.index = nil
+		return
+	}
+	if w.index != nil && w.index.Path() == path {
+		return
+	}
+	x, err := index.Open(path)
+	if err != nil {
+		w.sink.Logf(events.Warning, "record index unusable, rebuilding: %v", err)
+	}
+	w.index = x
+}
+
+// loadFile returns the record of a stats file from the index when it is
+// unchanged, and parses it otherwise.
+func (w *Watcher) loadFile(f statsFile) (models.ScenarioRecord, fileState, error) {
+	if w.index != nil {
+		if rec, ok := w.cachedRecord(f); ok {
+			rec.Source = f.source.Label
+			rec.HasTrace = traces.Exists(rec.FileName)
+			return rec, fileState{size: f.size, modTime: f.modTime, hash: rec.ContentHash}, nil
+		}
+	}
+	return w.parseFile(f, false)
+}
+
+// isCached reports whether f can be loaded from the index without parsing.
+func (w *Watcher) isCached(f statsFile) bool {
+	if w.index == nil {
+		return false
+	}
+	_, ok := w.cachedRecord(f)
+	return ok
+}
+
+// cachedRecord returns the index entry of f. Entries parsed with another
+// source time zone are treated as missing so they are parsed again.
+func (w *Watcher) cachedRecord(f statsFile) (models.ScenarioRecord, bool) {
+	rec, ok := w.index.Get(f.path, f.size, f.modTime)
+	if !ok || rec.PlayedAt != f.t.UTC().Format(time.RFC3339) {
+		return models.ScenarioRecord{}, false
+	}
+	return rec, true
+}
+
+// backfill parses the stats files beyond ParseExistingLimit that were not in
+// the index, adds them to the index and the sessions and re-emits the recent
+// list as "ScenariosLoaded". The files must already be marked seen.
+func (w *Watcher) backfill(files []statsFile) {
+	w.mu.RLock()
+	stop := w.stopCh
+	w.mu.RUnlock()
+	start := time.Now()
+	results := w.parseAll(files)
+
+	var added []models.ScenarioRecord
+	w.mu.Lock()
+	select {
+	case <-stop:
+		// Stopped (and possibly cleared) meanwhile; drop the results.
+		w.mu.Unlock()
+		return
+	default:
+	}
+	for i, res := range results {
+		if res.err != nil {
+			continue
+		}
+		full := files[i].path
+		if w.seen[full].hash != "" {
+			// Changed and ingested by the watch loop in the meantime
+			continue
+		}
+		w.seen[full] = res.st
+		if first, dup := w.byHash[res.st.hash]; dup && first != full {
+			continue
+		}
+		w.byHash[res.st.hash] = full
+		added = append(added, res.rec)
+	}
+	w.insertRecent(added)
+	w.mu.Unlock()
+	for i, res := range results {
+		if res.err != nil {
+			w.handleParseError(files[i], res.err)
+		}
+	}
+
+	w.sessions.AddAll(runsOf(added))
+	w.saveIndex()
+	w.sink.Logf(events.Info, "parsed %d older stats files in %s", len(added), time.Since(start).Round(time.Millisecond))
+	w.sink.Emit("ScenariosLoaded", w.GetRecent(0))
+}
+
+// lookup returns the record of path from recent, falling back to the index
+// for runs that fell out of recent.
+func (w *Watcher) lookup(path string) (models.ScenarioRecord, bool) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if i := w.recentIndex(path); i >= 0 {
+		return w.recent[i], true
+	}
+	if w.index == nil {
+		return models.ScenarioRecord{}, false
+	}
+	return w.index.Record(path)
+}
+
+// history returns every ingested run: recent plus, with the record index,
+// the older runs only kept there. Copies skipped as duplicates are left out.
+func (w *Watcher) history() []models.ScenarioRecord {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.index == nil {
+		return w.recent
+	}
+	inRecent := make(map[string]struct{}, len(w.recent))
+	for _, rec := range w.recent {
+		inRecent[rec.FilePath] = struct{}{}
+	}
+	out := append([]models.ScenarioRecord(nil), w.recent...)
+	for _, rec := range w.index.Records() {
+		if _, ok := inRecent[rec.FilePath]; ok || w.byHash[rec.ContentHash] != rec.FilePath {
+			continue
+		}
+		out = append(out, rec)
+	}
+	return out
+}
+
+// insertRecent adds out-of-order records to recent, keeping it ordered
+// oldest-first and bounded by the recent cap. Callers must hold w.mu.
+func (w *Watcher) insertRecent(recs []models.ScenarioRecord) {
+	if len(recs) == 0 {
+		return
+	}
+	w.recent = append(w.recent, recs...)
+	sort.SliceStable(w.recent, func(i, j int) bool {
+		return recordTime(w.recent[i]).Before(recordTime(w.recent[j]))
+	})
+	cap := w.effectiveRecentCap()
+	if cap > 0 && len(w.recent) > cap {
+		w.recent = w.recent[len(w.recent)-cap:]
+	}
+}
+
+// scheduleIndexSave writes the index once IndexSaveDelaySeconds have passed,
+// batching the changes made in between.
+func (w *Watcher) scheduleIndexSave() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.index == nil || w.indexTimer != nil {
+		return
+	}
+	w.indexTimer = time.AfterFunc(time.Duration(constants.IndexSaveDelaySeconds)*time.Second, w.saveIndex)
+}
+
+// saveIndex writes pending index changes to disk.
+func (w *Watcher) saveIndex() {
+	w.mu.Lock()
+	x := w.index
+	if w.indexTimer != nil {
+		w.indexTimer.Stop()
+		w.indexTimer = nil
+	}
+	w.mu.Unlock()
+	if x == nil {
+		return
+	}
+	if err := x.Save(); err != nil {
+		w.sink.Logf(events.Warning, "saving record index failed: %v", err)
+	}
+}
@@ -0,0 +1,74 @@
+package watcher
+
+import (
+	"path/filepath"
+	"testing"
+
+	"refleks/internal/events"
+	"refleks/internal/index"
+	"refleks/internal/models"
+)
+
+func TestIndexKeepsSummariesOnly(t *testing.T) {
+	dir, names := copyStats(t, 3)
+	t.Setenv("HOME", t.TempDir())
+	cfg := models.WatcherConfig{Path: dir, IndexPath: filepath.Join(t.TempDir(), "index.gob")}
+	if err := New(events.NewRecorder(), cfg).Scan(); err != nil {
+		t.Fatal(err)
+	}
+	// An empty source gets a run imported from an archive, with no file on disk.
+	archived := models.WatcherConfig{Path: t.TempDir(), IndexPath: filepath.Join(t.TempDir(), "index.gob")}
+	imp := New(events.NewRecorder(), archived)
+	if err := imp.Scan(); err != nil {
+		t.Fatal(err)
+	}
+	if prog, err := imp.ImportArchive(zipStats(t, names[:1]), ""); err != nil || prog.Imported != 1 {
+		t.Fatalf("import: %+v, %v", prog, err)
+	}
+
+	for _, c := range []models.WatcherConfig{cfg, archived} {
+		x, err := index.Open(c.IndexPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if x.Len() == 0 {
+			t.Fatalf("index %s is empty", c.IndexPath)
+		}
+		for _, rec := range x.Records() {
+			if rec.Events != nil || rec.Kills != nil || rec.Weapons != nil {
+				t.Fatalf("%s: index keeps the kill rows", rec.FilePath)
+			}
+			if rec.KillCount == 0 || rec.StartedAt == "" {
+				t.Fatalf("%s: summary incomplete: kills %d, started %q", rec.FilePath, rec.KillCount, rec.StartedAt)
+			}
+		}
+	}
+
+	// Both the live files and the archived run are loaded from the index and
+	// read their kill rows back on demand.
+	for _, c := range []models.WatcherConfig{cfg, archived} {
+		w := New(events.NewRecorder(), c)
+		if err := w.Scan(); err != nil {
+			t.Fatal(err)
+		}
+		recent := w.GetRecent(0)
+		if len(recent) == 0 {
+			t.Fatal("nothing loaded from the index")
+		}
+		for _, s := range recent {
+			if kills, _ := s.Stats["Kills"].(int); s.KillCount != kills {
+				t.Fatalf("%s: KillCount %d, Kills stat %d", s.FileName, s.KillCount, kills)
+			}
+			rec, err := w.GetDetail(s.FilePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(rec.Kills) != s.KillCount || len(rec.Events) != s.KillCount || len(rec.Weapons) == 0 {
+				t.Fatalf("%s: detail has %d kills, %d weapons; want %d kills", s.FilePath, len(rec.Kills), len(rec.Weapons), s.KillCount)
+			}
+			if rec.Weapons[0].Accuracy == 0 {
+				t.Fatalf("%s: weapon ratios not derived", s.FilePath)
+			}
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package watcher
+
+import "errors"
+
+// errNotifyUnsupported is returned by newNotifier on platforms without a
+// native file notification backend; the watcher then falls back to polling.
+var errNotifyUnsupported = errors.New("file notifications not supported on this platform")
+
+// notifyEvent reports a change to a path in a watched directory. A zero event
+// (empty Path) means events were lost and a rescan is needed.
+type notifyEvent struct {
+	Path string
+	Dir  bool
+}
+
+// notifier delivers change events for a set of watched directories (non-recursive).
+// The channel is closed when the notifier fails or is closed.
+type notifier interface {
+	Add(dir string) error
+	Events() <-chan notifyEvent
+	Close() error
+}
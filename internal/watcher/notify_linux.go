@@ -0,0 +1,121 @@
+//go:build linux
+
+package watcher
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"sync"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// inotifyNotifier watches directories using inotify.
+type inotifyNotifier struct {
+	fd     int
+	f      *os.File
+	events chan notifyEvent
+	// done is closed by Close so readLoop stops even when nobody drains events.
+	done      chan struct{}
+	closeOnce sync.Once
+
+	mu   sync.Mutex
+	dirs map[int]string // watch descriptor -> directory
+}
+
+// newNotifier creates an inotify instance; directories are added with Add.
+func newNotifier() (notifier, error) {
+	fd, err := unix.InotifyInit1(unix.IN_CLOEXEC | unix.IN_NONBLOCK)
+	if err != nil {
+		return nil, err
+	}
+	// A non-blocking fd wrapped in os.File uses the runtime poller, so Close
+	// unblocks a pending Read.
+	n := &inotifyNotifier{
+		fd:     fd,
+		f:      os.NewFile(uintptr(fd), "inotify"),
+		events: make(chan notifyEvent, 64),
+		done:   make(chan struct{}),
+		dirs:   make(map[int]string),
+	}
+	go n.readLoop()
+	return n, nil
+}
+
+func (n *inotifyNotifier) Add(dir string) error {
+	mask := uint32(unix.IN_CREATE | unix.IN_MODIFY | unix.IN_CLOSE_WRITE | unix.IN_MOVED_TO |
+		unix.IN_DELETE | unix.IN_MOVED_FROM)
+	wd, err := unix.InotifyAddWatch(n.fd, dir, mask)
+	if err != nil {
+		return err
+	}
+	n.mu.Lock()
+	n.dirs[wd] = dir
+	n.mu.Unlock()
+	return nil
+}
+
+func (n *inotifyNotifier) Events() <-chan notifyEvent { return n.events }
+
+func (n *inotifyNotifier) Close() error {
+	err := os.ErrClosed
+	n.closeOnce.Do(func() {
+		close(n.done)
+		err = n.f.Close()
+	})
+	return err
+}
+
+// send delivers ev unless the notifier was closed; it reports whether to go on.
+func (n *inotifyNotifier) send(ev notifyEvent) bool {
+	select {
+	case n.events <- ev:
+		return true
+	case <-n.done:
+		return false
+	}
+}
+
+func (n *inotifyNotifier) readLoop() {
+	defer close(n.events)
+	buf := make([]byte, 64*(unix.SizeofInotifyEvent+unix.NAME_MAX+1))
+	for {
+		cnt, err := n.f.Read(buf)
+		if err != nil {
+			return
+		}
+		for off := 0; off+unix.SizeofInotifyEvent <= cnt; {
+			ev := (*unix.InotifyEvent)(unsafe.Pointer(&buf[off]))
+			nameStart := off + unix.SizeofInotifyEvent
+			nameEnd := nameStart + int(ev.Len)
+			if nameEnd > cnt {
+				break
+			}
+			off = nameEnd
+			switch {
+			case ev.Mask&unix.IN_Q_OVERFLOW != 0:
+				if !n.send(notifyEvent{}) {
+					return
+				}
+			case ev.Mask&unix.IN_IGNORED != 0:
+				// Watch removed (directory deleted or unmounted)
+				n.mu.Lock()
+				delete(n.dirs, int(ev.Wd))
+				n.mu.Unlock()
+			case ev.Len > 0:
+				n.mu.Lock()
+				dir, ok := n.dirs[int(ev.Wd)]
+				n.mu.Unlock()
+				if !ok {
+					continue
+				}
+				name := string(bytes.TrimRight(buf[nameStart:nameEnd], "\x00"))
+				if !n.send(notifyEvent{Path: filepath.Join(dir, name), Dir: ev.Mask&unix.IN_ISDIR != 0}) {
+					return
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,8 @@
+//go:build !linux
+
+package watcher
+
+// newNotifier is not implemented on this platform; the watcher polls instead.
+func newNotifier() (notifier, error) {
+	return nil, errNotifyUnsupported
+}
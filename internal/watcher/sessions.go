@@ -0,0 +1,111 @@
+package watcher
+
+import (
+	"time"
+
+	"refleks/internal/constants"
+	"refleks/internal/models"
+	"refleks/internal/query"
+	"refleks/internal/sessions"
+)
+
+// sessionGap returns the configured session gap, or the default when unset.
+func sessionGap(cfg models.WatcherConfig) time.Duration {
+	if cfg.SessionGap > 0 {
+		return cfg.SessionGap
+	}
+	return time.Duration(constants.DefaultSessionGapMinutes) * time.Minute
+}
+
+// runOf returns the time span used to place a record in a session.
+func runOf(rec models.ScenarioRecord) sessions.Run {
+	end := recordTime(rec)
+	start, err := time.Parse(time.RFC3339, rec.StartedAt)
+	if err != nil {
+		start, _ = deriveScenarioWindow(end, rec.ScenarioStats, rec.Kills)
+	}
+	return sessions.Run{FilePath: rec.FilePath, Start: start, End: end}
+}
+
+// runsOf returns the runs of recs.
+func runsOf(recs []models.ScenarioRecord) []sessions.Run {
+	runs := make([]sessions.Run, 0, len(recs))
+	for _, rec := range recs {
+		runs = append(runs, runOf(rec))
+	}
+	return runs
+}
+
+// trackRun adds a newly ingested run to the sessions and emits
+// "SessionEnded"/"SessionStarted" when it opens a new session.
+func (w *Watcher) trackRun(rec models.ScenarioRecord) {
+	started, ended := w.sessions.Add(runOf(rec))
+	if ended != nil {
+		w.sink.Emit("SessionEnded", w.sessionModel(*ended))
+	}
+	if started != nil {
+		w.sink.Emit("SessionStarted", w.sessionModel(*started))
+	}
+	w.scheduleSessionEnd()
+}
+
+// scheduleSessionEnd emits "SessionEnded" once the latest session has been
+// idle for longer than the gap, re-arming itself until then.
+func (w *Watcher) scheduleSessionEnd() {
+	ended, wait := w.sessions.Expire(time.Now())
+	if ended != nil {
+		w.sink.Emit("SessionEnded", w.sessionModel(*ended))
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.sessionTimer != nil {
+		w.sessionTimer.Stop()
+		w.sessionTimer = nil
+	}
+	if wait > 0 {
+		w.sessionTimer = time.AfterFunc(wait, w.scheduleSessionEnd)
+	}
+}
+
+// GetSessions returns the sessions overlapping [from, to], most-recent-first.
+// Zero times leave the range open on that side.
+func (w *Watcher) GetSessions(from, to time.Time) []models.Session {
+	list := w.sessions.Sessions(from, to)
+	out := make([]models.Session, 0, len(list))
+	for _, s := range list {
+		out = append(out, w.sessionModel(s))
+	}
+	return out
+}
+
+// QueryScenarios filters, sorts and pages the ingested runs, reading those
+// beyond the recent cap from the record index. tags maps scenario names to user tags.
+func (w *Watcher) QueryScenarios(q models.ScenarioQuery, tags map[string][]string) (models.ScenarioPage, error) {
+	opts := query.Options{Tags: tags}
+	if q.SessionID != "" {
+		opts.Session = make(map[string]struct{})
+		if s, ok := w.sessions.Session(q.SessionID); ok {
+			for _, r := range s.Runs {
+				opts.Session[r.FilePath] = struct{}{}
+			}
+		}
+	}
+	return query.Run(w.history(), q, opts)
+}
+
+// sessionModel joins a session with the records of its runs. Runs whose
+// records are neither in recent nor in the index are left out of Items.
+func (w *Watcher) sessionModel(s sessions.Session) models.Session {
+	items := make([]models.ScenarioSummary, 0, len(s.Runs))
+	for i := len(s.Runs) - 1; i >= 0; i-- {
+		if rec, ok := w.lookup(s.Runs[i].FilePath); ok {
+			items = append(items, query.Summarize(rec))
+		}
+	}
+	return models.Session{
+		ID:    s.ID,
+		Start: s.Start.Format(time.RFC3339),
+		End:   s.End.Format(time.RFC3339),
+		Items: items,
+	}
+}
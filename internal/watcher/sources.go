@@ -0,0 +1,203 @@
+package watcher
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"refleks/internal/models"
+	"refleks/internal/parser"
+	"refleks/internal/query"
+	"refleks/internal/traces"
+)
+
+// statsFile is a stats CSV discovered in one of the watched sources.
+type statsFile struct {
+	path   string
+	source models.StatsSource
+	t      time.Time
+	// size and modTime are the file's on-disk state when it was listed.
+	size    int64
+	modTime time.Time
+}
+
+// sources returns the configured stats directories. A config with only Path
+// set is treated as a single non-recursive source.
+func (w *Watcher) sources() []models.StatsSource {
+	if len(w.cfg.Sources) > 0 {
+		return w.cfg.Sources
+	}
+	return []models.StatsSource{{Path: w.cfg.Path}}
+}
+
+// sourceFor returns the source that contains path, preferring the deepest match.
+func (w *Watcher) sourceFor(path string) (models.StatsSource, bool) {
+	var best models.StatsSource
+	found := false
+	for _, src := range w.sources() {
+		rel, err := filepath.Rel(src.Path, path)
+		if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+			continue
+		}
+		if !src.Recursive && filepath.Dir(rel) != "." {
+			continue
+		}
+		if !found || len(src.Path) > len(best.Path) {
+			best, found = src, true
+		}
+	}
+	return best, found
+}
+
+// listStatsFiles lists the stats CSVs of a source, descending into
+// subdirectories when the source is recursive.
+func listStatsFiles(src models.StatsSource) ([]statsFile, error) {
+	return listStatsFilesIn(src.Path, src)
+}
+
+func listStatsFilesIn(dir string, src models.StatsSource) ([]statsFile, error) {
+	loc := sourceLocation(src)
+	var files []statsFile
+	add := func(full string, d fs.DirEntry) {
+		name := filepath.Base(full)
+		if !isKovaaksStatsFile(name) {
+			return
+		}
+		info, err := parser.ParseFilenameIn(name, loc)
+		if err != nil {
+			return
+		}
+		fi, err := d.Info()
+		if err != nil {
+			// Removed between listing and stat
+			return
+		}
+		files = append(files, statsFile{path: full, source: src, t: info.DatePlayed, size: fi.Size(), modTime: fi.ModTime()})
+	}
+	if !src.Recursive {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range entries {
+			if !e.IsDir() {
+				add(filepath.Join(dir, e.Name()), e)
+			}
+		}
+		return files, nil
+	}
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// Skip unreadable subtrees but fail if the root itself is missing.
+			if p == dir {
+				return err
+			}
+			return nil
+		}
+		if !d.IsDir() {
+			add(p, d)
+		}
+		return nil
+	})
+	return files, err
+}
+
+// watchDirs returns every directory that needs a notification watch.
+func watchDirs(src models.StatsSource) []string {
+	if !src.Recursive {
+		return []string{src.Path}
+	}
+	var dirs []string
+	_ = filepath.WalkDir(src.Path, func(p string, d fs.DirEntry, err error) error {
+		if err == nil && d.IsDir() {
+			dirs = append(dirs, p)
+		}
+		return nil
+	})
+	return dirs
+}
+
+// locations caches loaded time zones by name.
+var locations sync.Map
+
+// loadLocation returns the named IANA time zone; an empty name is local time.
+func loadLocation(name string) (*time.Location, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return time.Local, nil
+	}
+	if loc, ok := locations.Load(name); ok {
+		return loc.(*time.Location), nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, err
+	}
+	locations.Store(name, loc)
+	return loc, nil
+}
+
+// sourceLocation returns the time zone stats file timestamps of src were
+// written in. Unknown zones fall back to local time (Start logs them).
+func sourceLocation(src models.StatsSource) *time.Location {
+	loc, err := loadLocation(src.Timezone)
+	if err != nil {
+		return time.Local
+	}
+	return loc
+}
+
+// TraceRuns lists the runs traces can belong to: every stats file in the
+// sources plus the records held from imports. Scores and scenario names are
+// filled in for parsed runs, including those only kept in the record index.
+// It fails when a source cannot be listed, since the list would then not be
+// complete.
+func (w *Watcher) TraceRuns() ([]traces.Run, error) {
+	var files []statsFile
+	for _, src := range w.sources() {
+		found, err := listStatsFiles(src)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, found...)
+	}
+	history := w.history()
+	byPath := make(map[string]models.ScenarioRecord, len(history))
+	// byName covers copies of a run in other sources, which history skips.
+	byName := make(map[string]models.ScenarioRecord, len(history))
+	for _, rec := range history {
+		byPath[rec.FilePath] = rec
+		byName[rec.FileName] = rec
+	}
+	runs := make([]traces.Run, 0, len(files)+len(history))
+	for _, f := range files {
+		run := traces.Run{FileName: filepath.Base(f.path), PlayedAt: f.t}
+		rec, ok := byPath[f.path]
+		if ok {
+			delete(byPath, f.path)
+		} else {
+			rec, ok = byName[run.FileName]
+		}
+		if ok {
+			run.ScenarioName = query.ScenarioName(rec)
+			run.Score = rec.ScenarioStats.Score
+		}
+		runs = append(runs, run)
+	}
+	// What is left are runs without a stats file on disk, i.e. imports.
+	for _, rec := range byPath {
+		if _, err := os.Stat(rec.FilePath); err == nil {
+			continue
+		}
+		runs = append(runs, traces.Run{
+			FileName:     rec.FileName,
+			ScenarioName: query.ScenarioName(rec),
+			Score:        rec.ScenarioStats.Score,
+			PlayedAt:     recordTime(rec),
+		})
+	}
+	return runs, nil
+}
@@ -0,0 +1,58 @@
+package watcher
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"refleks/internal/events"
+	"refleks/internal/models"
+	"refleks/internal/traces"
+)
+
+func TestTraceRunsBeyondRecentCap(t *testing.T) {
+	dir, names := copyStats(t, 5)
+	t.Setenv("HOME", t.TempDir())
+	traces.SetBaseDir(t.TempDir())
+	t.Cleanup(func() { traces.SetBaseDir("") })
+	for _, name := range names {
+		if err := traces.Save(traces.ScenarioData{FileName: name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Fill the index with every run, then load again keeping only two in recent.
+	cfg := models.WatcherConfig{Path: dir, IndexPath: filepath.Join(t.TempDir(), "index.gob")}
+	if err := New(events.NewRecorder(), cfg).Scan(); err != nil {
+		t.Fatal(err)
+	}
+	cfg.ParseExistingLimit = 2
+	w := New(events.NewRecorder(), cfg)
+	if err := w.Scan(); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(w.GetRecent(0)); n != 2 {
+		t.Fatalf("recent holds %d runs, want 2", n)
+	}
+
+	runs, err := w.TraceRuns()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != len(names) {
+		t.Fatalf("got %d runs, want %d", len(runs), len(names))
+	}
+	for _, r := range runs {
+		if r.ScenarioName == "" {
+			t.Fatalf("run %s has no scenario name", r.FileName)
+		}
+	}
+
+	rep, err := traces.GC(traces.Policy{KeepBestPerScenario: 1}, runs, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rep.BeyondBest != len(names)-1 || rep.Remaining != 1 {
+		t.Fatalf("report %+v, want all but the best trace deleted", rep)
+	}
+}
@@ -1,45 +1,69 @@
 package watcher
 
 import (
-	"context"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	goruntime "runtime"
 	"sort"
 	"strings"
 	"sync"
 	"time"
 
-	"github.com/wailsapp/wails/v2/pkg/runtime"
-
 	"refleks/internal/constants"
+	"refleks/internal/events"
+	"refleks/internal/importer"
+	"refleks/internal/index"
 	"refleks/internal/models"
 	"refleks/internal/parser"
+	"refleks/internal/query"
 	"refleks/internal/sens"
+	"refleks/internal/sessions"
 	"refleks/internal/traces"
-	"refleks/internal/util"
 )
 
-// Watcher monitors a directory for new stats files and emits events.
+// Watcher monitors a directory for new stats files and publishes events and
+// log messages to its sink.
 type Watcher struct {
-	ctx     context.Context
+	sink    events.EventSink
 	cfg     models.WatcherConfig
 	mu      sync.RWMutex
 	running bool
 	stopCh  chan struct{}
-	seen    map[string]struct{} // full file path set
+	seen    map[string]fileState // full file path -> last known state
+	// partial counts parse attempts for files that looked half-written.
+	partial map[string]int
+	// byHash maps a content hash to the first path ingested with that content,
+	// so copies of the same run in several sources are only ingested once.
+	byHash map[string]string
 
 	recent []models.ScenarioRecord
 	mouse  MouseProvider
+
+	// sessions groups the ingested runs; sessionTimer reports the latest one as ended.
+	sessions     *sessions.Tracker
+	sessionTimer *time.Timer
+
+	// index caches parsed records on disk (nil when disabled); indexTimer
+	// debounces saving it.
+	index      *index.Index
+	indexTimer *time.Timer
 }
 
-// New returns a new Watcher with the given config.
-func New(ctx context.Context, cfg models.WatcherConfig) *Watcher {
+// New returns a new Watcher with the given config that publishes to sink.
+func New(sink events.EventSink, cfg models.WatcherConfig) *Watcher {
 	return &Watcher{
-		ctx:    ctx,
-		cfg:    cfg,
-		stopCh: make(chan struct{}),
-		seen:   make(map[string]struct{}),
+		sink:     sink,
+		cfg:      cfg,
+		stopCh:   make(chan struct{}),
+		seen:     make(map[string]fileState),
+		partial:  make(map[string]int),
+		byHash:   make(map[string]string),
+		sessions: sessions.NewTracker(sessionGap(cfg)),
 	}
 }
 
@@ -56,7 +80,7 @@ func (w *Watcher) SetMouseProvider(p MouseProvider) {
 	w.mouse = p
 }
 
-// Start begins polling loop. It is safe to call once; subsequent calls return an error.
+// Start begins the watch loop. It is safe to call once; subsequent calls return an error.
 func (w *Watcher) Start() error {
 	w.mu.Lock()
 	if w.running {
@@ -66,16 +90,23 @@ func (w *Watcher) Start() error {
 	w.running = true
 	w.mu.Unlock()
 
-	// Do not create the directory if it doesn't exist. Just log and continue.
-	if _, err := os.Stat(w.cfg.Path); err != nil {
-		if os.IsNotExist(err) {
-			runtime.LogWarningf(w.ctx, "watch path does not exist: %s (will retry)", w.cfg.Path)
-		} else {
-			runtime.LogWarningf(w.ctx, "watch path not accessible: %s: %v", w.cfg.Path, err)
+	// Do not create the directories if they don't exist. Just log and continue.
+	for _, src := range w.sources() {
+		if _, err := os.Stat(src.Path); err != nil {
+			if os.IsNotExist(err) {
+				w.sink.Logf(events.Warning, "watch path does not exist: %s (will retry)", src.Path)
+			} else {
+				w.sink.Logf(events.Warning, "watch path not accessible: %s: %v", src.Path, err)
+			}
+		}
+		if _, err := loadLocation(src.Timezone); err != nil {
+			w.sink.Logf(events.Warning, "unknown timezone %q for %s, using local time: %v", src.Timezone, src.Path, err)
 		}
 	}
 
-	runtime.EventsEmit(w.ctx, "WatcherStarted", map[string]string{"path": w.cfg.Path})
+	w.sink.Emit("WatcherStarted", map[string]string{"path": w.cfg.Path})
+
+	w.openIndex()
 
 	// Optionally parse existing files once
 	if w.cfg.ParseExistingOnStart {
@@ -86,27 +117,74 @@ func (w *Watcher) Start() error {
 	return nil
 }
 
-// Stop stops the watcher.
+// Scan loads the existing stats files once without watching for changes,
+// for headless use. It honours ParseExistingLimit like Start does.
+func (w *Watcher) Scan() error {
+	w.openIndex()
+	err := w.scanOnce(true)
+	w.saveIndex()
+	return err
+}
+
+// Stop stops the watcher and flushes the record index.
 func (w *Watcher) Stop() error {
 	w.mu.Lock()
-	defer w.mu.Unlock()
 	if !w.running {
+		w.mu.Unlock()
 		return nil
 	}
 	close(w.stopCh)
 	w.running = false
+	if w.sessionTimer != nil {
+		w.sessionTimer.Stop()
+		w.sessionTimer = nil
+	}
 	w.stopCh = make(chan struct{})
+	w.mu.Unlock()
+	w.saveIndex()
 	return nil
 }
 
 func (w *Watcher) Clear() {
 	w.mu.Lock()
-	w.seen = make(map[string]struct{})
+	w.seen = make(map[string]fileState)
+	w.partial = make(map[string]int)
+	w.byHash = make(map[string]string)
 	w.recent = nil
 	w.mu.Unlock()
+	w.sessions.Reset(nil)
 }
 
+// loop waits for new, changed and removed stats files. It prefers OS file notifications and falls
+// back to polling the directory when they are unavailable.
 func (w *Watcher) loop() {
+	n, err := newNotifier()
+	if err == nil {
+		for _, src := range w.sources() {
+			for _, dir := range watchDirs(src) {
+				if err = n.Add(dir); err != nil {
+					break
+				}
+			}
+			if err != nil {
+				n.Close()
+				break
+			}
+		}
+	}
+	if err != nil {
+		w.sink.Logf(events.Info, "file notifications unavailable (%v); polling every %s", err, w.cfg.PollInterval)
+		w.pollLoop()
+		return
+	}
+	defer n.Close()
+	if !w.notifyLoop(n) {
+		w.sink.Logf(events.Warning, "file notifications stopped; falling back to polling every %s", w.cfg.PollInterval)
+		w.pollLoop()
+	}
+}
+
+func (w *Watcher) pollLoop() {
 	ticker := time.NewTicker(w.cfg.PollInterval)
 	defer ticker.Stop()
 	for {
@@ -119,112 +197,433 @@ func (w *Watcher) loop() {
 	}
 }
 
-// scanOnce lists directory and emits events for newly discovered files.
-func (w *Watcher) scanOnce(includeAll bool) error {
-	entries, err := os.ReadDir(w.cfg.Path)
+// notifyLoop ingests files reported by n once their size has stopped changing;
+// reported files that no longer exist are removed once every pending file has
+// settled.
+// It returns true when the watcher was stopped and false when n failed.
+func (w *Watcher) notifyLoop(n notifier) bool {
+	settle := time.Duration(constants.NotifySettleMillis) * time.Millisecond
+	// pending maps a file path to its last observed size (-1 when not yet observed).
+	pending := make(map[string]int64)
+	var check <-chan time.Time
+	// Half-written files are re-queued after a poll interval rather than the settle delay.
+	retry := make(chan string)
+	// removed holds vanished files until pending is empty, so the new name of
+	// a renamed file is ingested first and takes over the run.
+	var removed []string
+	stop := w.stopCh
+	for {
+		select {
+		case <-stop:
+			return true
+		case full := <-retry:
+			pending[full] = -1
+			if check == nil {
+				check = time.After(settle)
+			}
+		case ev, ok := <-n.Events():
+			if !ok {
+				return false
+			}
+			if ev.Path == "" {
+				// Events were dropped by the kernel; catch up with a full scan.
+				_ = w.scanOnce(false)
+				continue
+			}
+			if ev.Dir {
+				// New subdirectory in a recursive source: watch it and pick up its files.
+				if src, ok := w.sourceFor(ev.Path); ok && src.Recursive {
+					for _, dir := range watchDirs(models.StatsSource{Path: ev.Path, Recursive: true}) {
+						_ = n.Add(dir)
+					}
+					files, _ := listStatsFilesIn(ev.Path, src)
+					for _, sf := range files {
+						pending[sf.path] = -1
+					}
+				}
+			} else if isKovaaksStatsFile(filepath.Base(ev.Path)) {
+				pending[ev.Path] = -1
+			}
+			if check == nil && len(pending) > 0 {
+				check = time.After(settle)
+			}
+		case <-check:
+			check = nil
+			for full, prev := range pending {
+				fi, err := os.Stat(full)
+				if err != nil {
+					delete(pending, full)
+					if os.IsNotExist(err) {
+						removed = append(removed, full)
+					}
+					continue
+				}
+				// Kovaak's may still be writing; wait until the size is stable.
+				if fi.Size() == 0 || fi.Size() != prev {
+					pending[full] = fi.Size()
+					continue
+				}
+				delete(pending, full)
+				src, ok := w.sourceFor(full)
+				if !ok {
+					continue
+				}
+				if w.processFile(statsFile{path: full, source: src, size: fi.Size(), modTime: fi.ModTime()}) {
+					time.AfterFunc(w.cfg.PollInterval, func() {
+						select {
+						case retry <- full:
+						case <-stop:
+						}
+					})
+				}
+			}
+			if len(pending) > 0 {
+				check = time.After(settle)
+				continue
+			}
+			// A rename arrives as a removal plus a new file that needs two
+			// size checks; processing it first lets addRecent move the run.
+			for _, full := range removed {
+				w.removeFile(full)
+			}
+			removed = nil
+		}
+	}
+}
+
+// processFile parses a new or modified stats file and emits "ScenarioAdded",
+// or "ScenarioUpdated" when it rewrote or renamed a known run. Unchanged files
+// are skipped. It reports whether the file should be retried later.
+func (w *Watcher) processFile(f statsFile) bool {
+	w.mu.RLock()
+	prev, known := w.seen[f.path]
+	w.mu.RUnlock()
+	if known && !prev.changed(f) {
+		return false
+	}
+	rec, st, err := w.parseFile(f, true)
 	if err != nil {
-		return err
+		return w.handleParseError(f, err)
+	}
+	w.scheduleIndexSave()
+	change, from := w.addRecent(f.path, rec, st)
+	// Emit flat summaries to keep the IPC payloads small.
+	switch change {
+	case changeAdded:
+		w.sink.Emit("ScenarioAdded", query.Summarize(rec))
+		w.trackRun(rec)
+	case changeUpdated:
+		w.sink.Logf(events.Info, "stats file modified: %s", f.path)
+		w.sessions.Replace(f.path, runOf(rec))
+		w.sink.Emit("ScenarioUpdated", query.Summarize(rec))
+	case changeMoved:
+		w.sink.Logf(events.Info, "stats file renamed: %s -> %s", from, f.path)
+		moved := w.recordFor(f.path, rec)
+		w.sessions.Replace(from, runOf(moved))
+		w.sink.Emit("ScenarioRemoved", models.ScenarioRemoved{FilePath: from, FileName: filepath.Base(from)})
+		w.sink.Emit("ScenarioUpdated", query.Summarize(moved))
+	}
+	return false
+}
+
+// recordFor returns the stored record for path, or fallback when it is not in recent.
+func (w *Watcher) recordFor(path string, fallback models.ScenarioRecord) models.ScenarioRecord {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if i := w.recentIndex(path); i >= 0 {
+		return w.recent[i]
 	}
+	return fallback
+}
+
+// scanOnce lists the source directories and emits events for new, modified
+// and removed files.
+func (w *Watcher) scanOnce(includeAll bool) error {
 	// Build list with parsed timestamps so we can sort by date, not filename
-	type fileRec struct {
-		path string
-		t    time.Time
-	}
-	var files []fileRec
-	for _, e := range entries {
-		if e.IsDir() {
-			continue
-		}
-		name := e.Name()
-		if !isKovaaksStatsFile(name) {
-			continue
-		}
-		full := filepath.Join(w.cfg.Path, name)
-		info, err := parser.ParseFilename(name)
+	var files []statsFile
+	var firstErr error
+	listed := make(map[string]bool)
+	for _, src := range w.sources() {
+		found, err := listStatsFiles(src)
 		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
 			continue
 		}
-		files = append(files, fileRec{path: full, t: info.DatePlayed})
+		listed[src.Path] = true
+		files = append(files, found...)
+	}
+	// Sort by time ascending (oldest first); ties keep source order.
+	sort.SliceStable(files, func(i, j int) bool { return files[i].t.Before(files[j].t) })
+	if includeAll && w.index != nil && firstErr == nil {
+		// Forget cached files that are gone from every source.
+		keep := make(map[string]struct{}, len(files))
+		for _, fr := range files {
+			keep[fr.path] = struct{}{}
+		}
+		w.index.Retain(keep)
 	}
-	// Sort by time ascending (oldest first)
-	sort.Slice(files, func(i, j int) bool { return files[i].t.Before(files[j].t) })
-	// If includeAll with a limit, restrict to last N files
+	// If includeAll with a limit, restrict parsing to the last N files. Older
+	// files still load when cached; with an index the rest are parsed in the
+	// background, otherwise they are skipped.
+	var older []statsFile
 	if includeAll && w.cfg.ParseExistingLimit > 0 && len(files) > w.cfg.ParseExistingLimit {
-		// mark older files as seen so we don't parse them later
-		older := files[:len(files)-w.cfg.ParseExistingLimit]
+		newest := files[len(files)-w.cfg.ParseExistingLimit:]
+		var initial []statsFile
+		for _, fr := range files[:len(files)-w.cfg.ParseExistingLimit] {
+			if w.isCached(fr) {
+				initial = append(initial, fr)
+			} else {
+				older = append(older, fr)
+			}
+		}
+		// mark older files as seen so the watch loop does not parse them
 		w.mu.Lock()
 		for _, fr := range older {
-			w.seen[fr.path] = struct{}{}
+			w.seen[fr.path] = fileState{size: fr.size, modTime: fr.modTime}
 		}
 		w.mu.Unlock()
-		// keep only the last N files for parsing now
-		files = files[len(files)-w.cfg.ParseExistingLimit:]
+		files = append(initial, newest...)
 	}
+	if includeAll {
+		w.loadInitial(files)
+		if w.index != nil && len(older) > 0 {
+			go w.backfill(older)
+		}
+		return firstErr
+	}
+	present := make(map[string]struct{}, len(files))
 	for _, fr := range files {
-		full := fr.path
-		w.mu.RLock()
-		_, known := w.seen[full]
-		w.mu.RUnlock()
-		if known && !includeAll {
+		present[fr.path] = struct{}{}
+		w.processFile(fr)
+	}
+	w.removeMissing(listed, present)
+	return firstErr
+}
+
+// loadInitial parses existing files concurrently and appends them to recent in
+// the given (oldest-first) order, along with runs imported earlier. A single
+// "ScenariosLoaded" event carrying the loaded records, most-recent-first, is
+// emitted once all files are parsed.
+func (w *Watcher) loadInitial(files []statsFile) {
+	results := w.parseAll(files)
+	loaded := make([]models.ScenarioRecord, 0, len(files))
+	for i, res := range results {
+		if res.err != nil {
+			w.handleParseError(files[i], res.err)
 			continue
 		}
+		if change, _ := w.addRecent(files[i].path, res.rec, res.st); change == changeAdded {
+			loaded = append(loaded, res.rec)
+		}
+	}
+	if archived := w.loadArchived(); len(archived) > 0 {
+		loaded = append(loaded, archived...)
+		sort.SliceStable(loaded, func(i, j int) bool { return recordTime(loaded[i]).Before(recordTime(loaded[j])) })
+	}
+	// Sessions span everything loaded, not only what stays in recent.
+	runs := runsOf(loaded)
+	// Match GetRecent ordering (most-recent-first) and the recent cap.
+	if cap := w.effectiveRecentCap(); cap > 0 && len(loaded) > cap {
+		loaded = loaded[len(loaded)-cap:]
+	}
+	for i, j := 0, len(loaded)-1; i < j; i, j = i+1, j-1 {
+		loaded[i], loaded[j] = loaded[j], loaded[i]
+	}
+	w.sink.Emit("ScenariosLoaded", query.Summaries(loaded))
 
-		rec, err := w.parseFile(full)
-		if err != nil {
-			runtime.LogErrorf(w.ctx, "parse error for %s: %v", full, err)
-			continue
+	w.sessions.Reset(runs)
+	w.scheduleSessionEnd()
+	w.saveIndex()
+}
+
+// parseResult is the outcome of loading one stats file.
+type parseResult struct {
+	rec models.ScenarioRecord
+	st  fileState
+	err error
+}
+
+// parseAll loads files concurrently (from the index when possible) and
+// returns the results in the same order.
+func (w *Watcher) parseAll(files []statsFile) []parseResult {
+	results := make([]parseResult, len(files))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for n := w.scanWorkers(); n > 0; n-- {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				rec, st, err := w.loadFile(files[i])
+				results[i] = parseResult{rec: rec, st: st, err: err}
+			}
+		}()
+	}
+	for i := range files {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return results
+}
+
+// scanWorkers returns the size of the parsing worker pool.
+func (w *Watcher) scanWorkers() int {
+	if w.cfg.ScanWorkers > 0 {
+		return w.cfg.ScanWorkers
+	}
+	return goruntime.NumCPU()
+}
+
+// addRecent records a successfully parsed file and stores its record in recent.
+// A rewritten file replaces its run in place; content already ingested from a
+// path that no longer exists moves that run to full (a rename). Content that is
+// still present under another path is skipped as a duplicate. It returns the
+// resulting change and, for changeMoved, the previous path.
+func (w *Watcher) addRecent(full string, rec models.ScenarioRecord, st fileState) (recordChange, string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	delete(w.partial, full)
+	prev, known := w.seen[full]
+	w.seen[full] = st
+	if known && prev.hash != "" {
+		if prev.hash == st.hash {
+			// Touched but not rewritten
+			return changeNone, ""
+		}
+		// A file skipped as a duplicate has no run of its own; treat its new
+		// content like a new file below.
+		if w.byHash[prev.hash] == full {
+			delete(w.byHash, prev.hash)
+			if _, dup := w.byHash[st.hash]; !dup {
+				w.byHash[st.hash] = full
+			}
+			if i := w.recentIndex(full); i >= 0 {
+				w.recent[i] = rec
+				return changeUpdated, ""
+			}
+			// The run already fell out of recent; nothing to refresh.
+			return changeNone, ""
 		}
+	}
+	if first, dup := w.byHash[st.hash]; dup && first != full {
+		if _, err := os.Stat(first); err == nil {
+			w.sink.Logf(events.Debug, "skipping %s: same content as %s", full, first)
+			return changeNone, ""
+		}
+		// The first copy is gone: the file was renamed or moved.
+		w.byHash[st.hash] = full
+		if w.moveRecord(first, &rec) {
+			return changeMoved, first
+		}
+		return changeNone, ""
+	}
+	w.byHash[st.hash] = full
+	w.recent = append(w.recent, rec)
+	cap := w.effectiveRecentCap()
+	if cap > 0 && len(w.recent) > cap {
+		w.recent = w.recent[len(w.recent)-cap:]
+	}
+	return changeAdded, ""
+}
 
-		w.mu.Lock()
-		w.seen[full] = struct{}{}
-		w.recent = append(w.recent, rec)
-		cap := w.effectiveRecentCap()
-		if cap > 0 && len(w.recent) > cap {
-			w.recent = w.recent[len(w.recent)-cap:]
+// handleParseError logs a parse failure and reports whether the file should be
+// retried. Half-written files are left unseen so a later scan retries them, up to
+// MaxPartialParseRetries attempts.
+func (w *Watcher) handleParseError(f statsFile, err error) bool {
+	full := f.path
+	if errors.Is(err, parser.ErrPartialFile) {
+		if w.retryPartial(full) {
+			w.sink.Logf(events.Debug, "stats file still being written, will retry: %s", full)
+			return true
 		}
+		w.sink.Logf(events.Warning, "giving up on incomplete stats file %s: %v", full, err)
+		w.mu.Lock()
+		// Keep the previous hash so a rewritten run stays linked to its record.
+		st := w.seen[full]
+		st.size, st.modTime = f.size, f.modTime
+		w.seen[full] = st
 		w.mu.Unlock()
+		return false
+	}
+	w.sink.Logf(events.Error, "parse error for %s: %v", full, err)
+	return false
+}
 
-		// Emit a flat ScenarioRecord to simplify the IPC contract.
-		runtime.EventsEmit(w.ctx, "ScenarioAdded", rec)
+// retryPartial records another attempt at parsing a half-written file and
+// reports whether it should be retried on a later scan.
+func (w *Watcher) retryPartial(path string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.partial[path]++
+	if w.partial[path] > constants.MaxPartialParseRetries {
+		delete(w.partial, path)
+		return false
 	}
-	return nil
+	return true
 }
 
-func (w *Watcher) parseFile(fullPath string) (models.ScenarioRecord, error) {
-	info, err := parser.ParseFilename(filepath.Base(fullPath))
+// parseFile reads and parses a stats file, returning its record along with
+// the file state it was parsed from. live marks a run that just finished (see
+// buildRecord); files loaded at startup or in the backfill are not live.
+func (w *Watcher) parseFile(f statsFile, live bool) (models.ScenarioRecord, fileState, error) {
+	name := filepath.Base(f.path)
+	info, err := parser.ParseFilenameIn(name, sourceLocation(f.source))
+	if err != nil {
+		return models.ScenarioRecord{}, fileState{}, err
+	}
+	// Read the whole file once: stats CSVs are small and the bytes double as
+	// the input for the content hash used to deduplicate sources.
+	b, err := os.ReadFile(f.path)
 	if err != nil {
-		return models.ScenarioRecord{}, err
+		return models.ScenarioRecord{}, fileState{}, err
 	}
-	events, stats, err := parser.ParseStatsFile(fullPath)
+	sf, err := parser.Parse(bytes.NewReader(b), name)
 	if err != nil {
-		return models.ScenarioRecord{}, err
+		return models.ScenarioRecord{}, fileState{}, err
+	}
+	rec := w.buildRecord(f.path, info, sf, live)
+	rec.Source = f.source.Label
+	rec.ContentHash = contentHash(b)
+	if w.index != nil {
+		w.index.Put(f.path, f.size, f.modTime, rec)
+	}
+	return rec, fileState{size: f.size, modTime: f.modTime, hash: rec.ContentHash}, nil
+}
+
+// contentHash returns the hex SHA-256 of raw stats file content.
+func contentHash(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// buildRecord derives stats and assembles a ScenarioRecord from parsed content.
+// filePath identifies the source (a disk path or an archive entry). When live is
+// set the run just finished, so a mouse trace may be captured for it.
+func (w *Watcher) buildRecord(filePath string, info parser.FilenameInfo, sf parser.StatsFile, live bool) models.ScenarioRecord {
+	if !sf.Diagnostics.Empty() {
+		w.sink.Logf(events.Warning, "parse warnings for %s: %s", filePath, sf.Diagnostics)
 	}
+	stats := sf.Stats
+	st := sf.ScenarioStats
 
-	// Augment stats with derived fields
+	// Augment stats with derived fields. The typed view is the source of truth;
+	// the generic map mirrors it for the frontend.
 	stats["Date Played"] = info.DatePlayed.Format(time.RFC3339)
+	playedAt := info.DatePlayed.UTC().Format(time.RFC3339)
 	// Accuracy = Hit Count / (Hit Count + Miss Count)
-	var hit, miss float64
-	if v, ok := stats["Hit Count"]; ok {
-		hit = util.ToFloat(v)
-	}
-	if v, ok := stats["Miss Count"]; ok {
-		miss = util.ToFloat(v)
-	}
-	denom := hit + miss
-	if denom > 0 {
-		stats["Accuracy"] = hit / denom
-	} else {
-		stats["Accuracy"] = 0.0
+	if denom := st.HitCount + st.MissCount; denom > 0 {
+		st.Accuracy = float64(st.HitCount) / float64(denom)
 	}
+	stats["Accuracy"] = st.Accuracy
 
 	// Real Avg TTK = average time between consecutive kill events (in seconds)
-	if len(events) >= 2 {
+	if len(sf.Kills) >= 2 {
 		var times []time.Time
-		for _, row := range events {
-			if len(row) < 2 {
-				continue
-			}
-			if t, ok := parseTODOnDate(row[1], info.DatePlayed); ok {
+		for _, k := range sf.Kills {
+			if t, ok := parseClockBefore(k.Timestamp, info.DatePlayed); ok {
 				times = append(times, t)
 			}
 		}
@@ -238,77 +637,83 @@ func (w *Watcher) parseFile(fullPath string) (models.ScenarioRecord, error) {
 			}
 			intervals := len(times) - 1
 			if intervals > 0 {
-				stats["Real Avg TTK"] = sum.Seconds() / float64(intervals)
+				st.RealAvgTTK = sum.Seconds() / float64(intervals)
+				stats["Real Avg TTK"] = st.RealAvgTTK
 			}
 		}
 	}
 
+	// Per-weapon accuracy and damage efficiency from the weapon summary table
+	deriveWeaponStats(sf.Weapons)
+
 	// Sensitivity normalized to cm/360 for filtering and charts. Always set; 0 means unsupported.
-	if cm, _ := sens.Cm360FromStats(stats); true {
-		stats["cm/360"] = cm
-	}
+	st.Cm360, _ = sens.Cm360FromScenarioStats(st)
+	stats["cm/360"] = st.Cm360
 
 	rec := models.ScenarioRecord{
-		FilePath: fullPath,
-		FileName: filepath.Base(fullPath),
-		Stats:    stats,
-		Events:   events,
-	}
+		FilePath:      filePath,
+		FileName:      filepath.Base(filePath),
+		Mode:          info.Mode,
+		PlayedAt:      playedAt,
+		Stats:         stats,
+		ScenarioStats: st,
+		Events:        sf.Events,
+		Kills:         sf.Kills,
+		Weapons:       sf.Weapons,
+		KillCount:     len(sf.Kills),
+	}
+	start, end := deriveScenarioWindow(info.DatePlayed, st, sf.Kills)
+	rec.StartedAt = start.UTC().Format(time.RFC3339)
 
 	// Optionally enrich with mouse trace based on Challenge Start -> DatePlayed interval
 	w.mu.RLock()
 	mp := w.mouse
 	w.mu.RUnlock()
-	if mp != nil && mp.Enabled() {
-		start, end := deriveScenarioWindow(info.DatePlayed, stats, events)
+	if live && mp != nil && mp.Enabled() {
 		if !start.IsZero() && !end.IsZero() && start.Before(end) {
 			rec.MouseTrace = mp.GetRange(start, end)
 			// debug
-			runtime.LogDebugf(w.ctx, "MouseTrace: %d points for %s in window %s - %s", len(rec.MouseTrace), rec.FileName, start.Format(time.RFC3339), end.Format(time.RFC3339))
+			w.sink.Logf(events.Debug, "MouseTrace: %d points for %s in window %s - %s", len(rec.MouseTrace), rec.FileName, start.Format(time.RFC3339), end.Format(time.RFC3339))
 		}
 	}
 
-	// If we captured a trace, persist it to disk for future reloads.
+	// If we captured a trace, persist it to disk for future reloads. Traces are
+	// large, so the record only keeps it when it could not be saved.
 	if len(rec.MouseTrace) > 0 {
 		// Only write if not already present to avoid churn.
-		if !traces.Exists(rec.FileName) {
-			_ = traces.Save(traces.ScenarioData{
-				Version:      1,
-				FileName:     rec.FileName,
-				ScenarioName: info.ScenarioName,
-				DatePlayed:   info.DatePlayed.Format(time.RFC3339),
-				MouseTrace:   rec.MouseTrace,
-			})
-		}
-	} else {
-		// No live capture available (e.g., after restart). Attempt to load persisted data.
 		if traces.Exists(rec.FileName) {
-			if sd, err := traces.Load(rec.FileName); err == nil && len(sd.MouseTrace) > 0 {
-				rec.MouseTrace = sd.MouseTrace
-			}
+			rec.MouseTrace = nil
+		} else if err := traces.Save(traces.ScenarioData{
+			FileName:     rec.FileName,
+			ScenarioName: info.ScenarioName,
+			DatePlayed:   info.DatePlayed.Format(time.RFC3339),
+			MouseTrace:   rec.MouseTrace,
+		}); err == nil {
+			rec.MouseTrace = nil
 		}
+		rec.HasTrace = true
+	} else {
+		// No live capture available (e.g., after restart); a trace may be persisted.
+		rec.HasTrace = traces.Exists(rec.FileName)
 	}
-	return rec, nil
+	return rec
 }
 
 // deriveScenarioWindow attempts to compute the [start, end] timespan of a scenario.
 // end is taken from the filename timestamp (DatePlayed). Start prefers the
-// "Challenge Start" key in stats, falling back to the first event timestamp.
-func deriveScenarioWindow(end time.Time, stats map[string]any, events [][]string) (time.Time, time.Time) {
-	// Try stats["Challenge Start"] first
+// "Challenge Start" stat, falling back to the first event timestamp.
+func deriveScenarioWindow(end time.Time, st models.ScenarioStats, kills []models.KillEvent) (time.Time, time.Time) {
+	// Try Challenge Start first
 	var start time.Time
-	if v, ok := stats["Challenge Start"]; ok {
-		if s, ok := v.(string); ok {
-			if t, ok := parseTODOnDate(s, end); ok {
-				start = t
-			}
+	if st.ChallengeStart != "" {
+		if t, ok := parseClockBefore(st.ChallengeStart, end); ok {
+			start = t
 		}
 	}
 	// Do NOT use "Fight Time" directly: its units vary and often represent active time, not total duration.
 	// Fallback to the first event timestamp's time-of-day
-	if start.IsZero() && len(events) > 0 && len(events[0]) > 1 {
-		ts := events[0][1]
-		if t, ok := parseTODOnDate(ts, end); ok {
+	if start.IsZero() && len(kills) > 0 {
+		if t, ok := parseClockBefore(kills[0].Timestamp, end); ok {
 			start = t
 		}
 	}
@@ -316,15 +721,31 @@ func deriveScenarioWindow(end time.Time, stats map[string]any, events [][]string
 	if start.IsZero() {
 		start = end.Add(-60 * time.Second)
 	}
-	// If start ended up after end (e.g., crossed midnight), shift by -1 day
-	if start.After(end) {
-		start = start.AddDate(0, 0, -1)
-	}
 	return start, end
 }
 
-// parseTODOnDate parses a clock time string onto the provided date.
-func parseTODOnDate(s string, date time.Time) (time.Time, bool) {
+// deriveWeaponStats fills the derived ratios of each weapon summary in place.
+func deriveWeaponStats(weapons []models.WeaponSummary) {
+	for i := range weapons {
+		ws := &weapons[i]
+		if ws.Shots > 0 {
+			ws.Accuracy = float64(ws.Hits) / float64(ws.Shots)
+		}
+		if ws.DamagePossible > 0 {
+			ws.Efficiency = ws.DamageDone / ws.DamagePossible
+		}
+	}
+}
+
+// clockSkew is how far a clock time written during a run may lie after the
+// run's end (the filename timestamp) and still be placed on the same day.
+const clockSkew = time.Minute
+
+// parseClockBefore parses a clock time string written during the run that
+// ended at end. The time is placed on end's date in end's time zone, or on the
+// previous day when that would put it after the end, so runs crossing
+// midnight stay in order. time.Date resolves clock times around DST changes.
+func parseClockBefore(s string, end time.Time) (time.Time, bool) {
 	// Support common formats with/without fractional seconds
 	layouts := []string{
 		"15:04:05.000000",
@@ -332,17 +753,21 @@ func parseTODOnDate(s string, date time.Time) (time.Time, bool) {
 		"15:04:05",
 	}
 	for _, layout := range layouts {
-		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
-			return time.Date(date.Year(), date.Month(), date.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), date.Location()), true
+		c, err := time.Parse(layout, strings.TrimSpace(s))
+		if err != nil {
+			continue
+		}
+		t := time.Date(end.Year(), end.Month(), end.Day(), c.Hour(), c.Minute(), c.Second(), c.Nanosecond(), end.Location())
+		if t.After(end.Add(clockSkew)) {
+			t = time.Date(end.Year(), end.Month(), end.Day()-1, c.Hour(), c.Minute(), c.Second(), c.Nanosecond(), end.Location())
 		}
+		return t, true
 	}
 	return time.Time{}, false
 }
 
-// removed duplicate toFloat: use util.ToFloat instead
-
-// GetRecent returns up to limit most recent scenarios.
-func (w *Watcher) GetRecent(limit int) []models.ScenarioRecord {
+// GetRecent returns summaries of up to limit most recent scenarios.
+func (w *Watcher) GetRecent(limit int) []models.ScenarioSummary {
 	w.mu.RLock()
 	defer w.mu.RUnlock()
 	total := len(w.recent)
@@ -352,14 +777,72 @@ func (w *Watcher) GetRecent(limit int) []models.ScenarioRecord {
 	if limit <= 0 || limit > total {
 		limit = total
 	}
-	out := make([]models.ScenarioRecord, limit)
+	out := make([]models.ScenarioSummary, limit)
 	// Return most-recent-first: copy from the end backwards
 	for i := 0; i < limit; i++ {
-		out[i] = w.recent[total-1-i]
+		out[i] = query.Summarize(w.recent[total-1-i])
 	}
 	return out
 }
 
+// GetDetail returns the full record of a stats file, including its kill rows
+// and persisted mouse trace. Files neither in recent nor in the index are
+// parsed from disk; index records get their kill rows from the stats file or
+// archive entry they came from.
+func (w *Watcher) GetDetail(filePath string) (models.ScenarioRecord, error) {
+	rec, ok := w.lookup(filePath)
+	if !ok {
+		src, ok := w.sourceFor(filePath)
+		if !ok {
+			return models.ScenarioRecord{}, fmt.Errorf("unknown scenario: %s", filePath)
+		}
+		info, err := parser.ParseFilenameIn(filepath.Base(filePath), sourceLocation(src))
+		if err != nil {
+			return models.ScenarioRecord{}, err
+		}
+		sf, err := parser.ParseStatsFile(filePath)
+		if err != nil && !errors.Is(err, parser.ErrPartialFile) {
+			return models.ScenarioRecord{}, err
+		}
+		rec = w.buildRecord(filePath, info, sf, false)
+	} else if rec.Events == nil && rec.Kills == nil && rec.Weapons == nil {
+		// Records loaded from the index only hold the summary.
+		if err := loadRows(&rec); err != nil {
+			w.sink.Logf(events.Warning, "kill rows of %s not loaded: %v", rec.FileName, err)
+		}
+	}
+	if len(rec.MouseTrace) == 0 && rec.HasTrace {
+		if sd, err := traces.Load(rec.FileName); err == nil {
+			rec.MouseTrace = sd.MouseTrace
+		} else if !errors.Is(err, os.ErrNotExist) {
+			w.sink.Logf(events.Warning, "mouse trace of %s not loaded: %v", rec.FileName, err)
+		}
+	}
+	return rec, nil
+}
+
+// loadRows fills in the kill rows and weapon table of rec, which the record
+// index leaves out, from its stats file or archive entry.
+func loadRows(rec *models.ScenarioRecord) error {
+	var b []byte
+	var err error
+	if archive, entry, ok := importer.SplitEntryPath(rec.FilePath); ok {
+		b, err = importer.ReadEntry(archive, entry)
+	} else {
+		b, err = os.ReadFile(rec.FilePath)
+	}
+	if err != nil {
+		return err
+	}
+	sf, err := parser.Parse(bytes.NewReader(b), rec.FileName)
+	if err != nil && !errors.Is(err, parser.ErrPartialFile) {
+		return err
+	}
+	deriveWeaponStats(sf.Weapons)
+	rec.Events, rec.Kills, rec.Weapons = sf.Events, sf.Kills, sf.Weapons
+	return nil
+}
+
 // IsRunning indicates if the watcher loop is active.
 func (w *Watcher) IsRunning() bool {
 	w.mu.RLock()
@@ -375,35 +858,33 @@ func (w *Watcher) UpdateConfig(cfg models.WatcherConfig) error {
 		return errors.New("cannot update config while running")
 	}
 	w.cfg = cfg
+	w.sessions.SetGap(sessionGap(cfg))
 	return nil
 }
 
-// ReloadTraces attempts to reload persisted mouse traces for recent scenarios
-// from the traces storage directory. For any records that gain a non-empty
-// MouseTrace as a result, a 'ScenarioUpdated' event is emitted.
+// ReloadTraces re-checks which recent scenarios have a persisted mouse trace
+// in the traces storage directory (e.g. after it moved). For any records whose
+// HasTrace flag changes, a 'ScenarioUpdated' event is emitted.
 func (w *Watcher) ReloadTraces() int {
 	// Copy updated records to emit outside the lock
 	var toEmit []models.ScenarioRecord
 	w.mu.Lock()
 	for i := range w.recent {
 		rec := w.recent[i]
-		// Attempt to load persisted trace
-		if traces.Exists(rec.FileName) {
-			if sd, err := traces.Load(rec.FileName); err == nil {
-				if len(sd.MouseTrace) > 0 {
-					if !equalMouseTrace(rec.MouseTrace, sd.MouseTrace) {
-						rec.MouseTrace = sd.MouseTrace
-						w.recent[i] = rec
-						toEmit = append(toEmit, rec)
-					}
-				}
-			}
+		if len(rec.MouseTrace) > 0 {
+			// Unsaved live capture; still in memory.
+			continue
+		}
+		if has := traces.Exists(rec.FileName); has != rec.HasTrace {
+			rec.HasTrace = has
+			w.recent[i] = rec
+			toEmit = append(toEmit, rec)
 		}
 	}
 	w.mu.Unlock()
 
 	for _, rec := range toEmit {
-		runtime.EventsEmit(w.ctx, "ScenarioUpdated", rec)
+		w.sink.Emit("ScenarioUpdated", query.Summarize(rec))
 	}
 	return len(toEmit)
 }
@@ -414,22 +895,14 @@ func isKovaaksStatsFile(name string) bool {
 	return strings.HasSuffix(lower, " stats.csv")
 }
 
-// equalMouseTrace provides a fast equality check for two mouse traces.
-func equalMouseTrace(a, b []models.MousePoint) bool {
-	if len(a) != len(b) {
-		return false
-	}
-	for i := range a {
-		if !a[i].TS.Equal(b[i].TS) || a[i].X != b[i].X || a[i].Y != b[i].Y || a[i].Buttons != b[i].Buttons {
-			return false
-		}
-	}
-	return true
-}
-
-// effectiveRecentCap returns the in-memory cap for recent scenarios.
-// If ParseExistingLimit is zero (parse all), we still bound memory to a sensible default.
+// effectiveRecentCap returns the in-memory cap for recent scenarios, or 0 for
+// no cap. Unless RecentCap is set, it follows ParseExistingLimit, and when
+// that is zero (parse all) memory is still bounded by a sensible default;
+// older runs stay reachable through the record index.
 func (w *Watcher) effectiveRecentCap() int {
+	if w.cfg.RecentCap != 0 {
+		return max(w.cfg.RecentCap, 0)
+	}
 	cap := w.cfg.ParseExistingLimit
 	if cap <= 0 {
 		cap = constants.DefaultRecentCap
@@ -0,0 +1,171 @@
+package watcher
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"refleks/internal/events"
+	"refleks/internal/models"
+)
+
+const statsFixtures = "../../testdata/stats"
+
+// copyStats copies the first n stats fixtures (oldest first) into a new
+// temporary directory and returns it with the copied names.
+func copyStats(t *testing.T, n int) (string, []string) {
+	t.Helper()
+	entries, err := os.ReadDir(statsFixtures)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, e := range entries {
+		if isKovaaksStatsFile(e.Name()) {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+	if len(names) < n {
+		t.Fatalf("need %d fixtures, have %d", n, len(names))
+	}
+	dir := t.TempDir()
+	for _, name := range names[:n] {
+		copyFile(t, filepath.Join(statsFixtures, name), filepath.Join(dir, name))
+	}
+	return dir, names[:n]
+}
+
+func copyFile(t *testing.T, from, to string) {
+	t.Helper()
+	b, err := os.ReadFile(from)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(to, b, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// newTestWatcher returns a watcher on dir recording into a Recorder. HOME is
+// redirected so trace lookups never touch the real traces directory.
+func newTestWatcher(t *testing.T, dir string) (*Watcher, *events.Recorder) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("USERPROFILE", os.Getenv("HOME"))
+	rec := events.NewRecorder()
+	w := New(rec, models.WatcherConfig{Path: dir, PollInterval: 50 * time.Millisecond, ParseExistingOnStart: true})
+	return w, rec
+}
+
+// waitFor polls rec until cond holds for its events or the deadline passes.
+func waitFor(t *testing.T, rec *events.Recorder, what string, cond func([]events.Event) bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if cond(rec.Events()) {
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %s; got %v", what, eventNames(rec.Events()))
+}
+
+func eventNames(evs []events.Event) []string {
+	out := make([]string, 0, len(evs))
+	for _, e := range evs {
+		out = append(out, e.Name)
+	}
+	return out
+}
+
+func TestScanEmitsScenariosLoaded(t *testing.T) {
+	dir, names := copyStats(t, 5)
+	w, rec := newTestWatcher(t, dir)
+	if err := w.Scan(); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded := rec.Events("ScenariosLoaded")
+	if len(loaded) != 1 {
+		t.Fatalf("got %d ScenariosLoaded events, want 1", len(loaded))
+	}
+	items, ok := loaded[0].Data.([]models.ScenarioSummary)
+	if !ok {
+		t.Fatalf("ScenariosLoaded carries %T", loaded[0].Data)
+	}
+	if len(items) != len(names) {
+		t.Fatalf("loaded %d runs, want %d", len(items), len(names))
+	}
+	// Most-recent-first, matching GetRecent.
+	if items[0].FileName != names[len(names)-1] || items[len(items)-1].FileName != names[0] {
+		t.Fatalf("order: first %q, last %q", items[0].FileName, items[len(items)-1].FileName)
+	}
+	if got := rec.Events("ScenarioAdded"); len(got) != 0 {
+		t.Fatalf("initial scan emitted %d ScenarioAdded events", len(got))
+	}
+	if warns := rec.Logs(events.Warning); len(warns) != 0 {
+		t.Fatalf("unexpected warnings: %v", warns)
+	}
+}
+
+func TestWatchAddsAndRenames(t *testing.T) {
+	dir, names := copyStats(t, 2)
+	w, rec := newTestWatcher(t, dir)
+	if err := w.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer w.Stop()
+	if got := rec.Events("ScenariosLoaded"); len(got) != 1 {
+		t.Fatalf("got %d ScenariosLoaded events, want 1", len(got))
+	}
+	rec.Reset()
+	// The watch loop registers its directories in the background.
+	time.Sleep(200 * time.Millisecond)
+
+	// A new run shows up as a single ScenarioAdded.
+	entries, _ := os.ReadDir(statsFixtures)
+	var next string
+	for _, e := range entries {
+		if isKovaaksStatsFile(e.Name()) && e.Name() > names[len(names)-1] {
+			next = e.Name()
+			break
+		}
+	}
+	copyFile(t, filepath.Join(statsFixtures, next), filepath.Join(dir, next))
+	waitFor(t, rec, "ScenarioAdded", func(evs []events.Event) bool {
+		for _, e := range evs {
+			if s, ok := e.Data.(models.ScenarioSummary); ok && e.Name == "ScenarioAdded" && s.FileName == next {
+				return true
+			}
+		}
+		return false
+	})
+	rec.Reset()
+
+	// Renaming keeps the run: it is removed under the old path and updated
+	// under the new one, never added again.
+	renamed := "Renamed" + next[2:]
+	if err := os.Rename(filepath.Join(dir, next), filepath.Join(dir, renamed)); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, rec, "ScenarioUpdated", func(evs []events.Event) bool {
+		for _, e := range evs {
+			if s, ok := e.Data.(models.ScenarioSummary); ok && e.Name == "ScenarioUpdated" && s.FileName == renamed {
+				return true
+			}
+		}
+		return false
+	})
+	if got := rec.Events("ScenarioAdded"); len(got) != 0 {
+		t.Fatalf("rename emitted ScenarioAdded: %v", eventNames(rec.Events()))
+	}
+	if got := rec.Events("ScenarioRemoved"); len(got) != 1 {
+		t.Fatalf("got %d ScenarioRemoved events, want 1", len(got))
+	}
+	if n := len(w.GetRecent(0)); n != len(names)+1 {
+		t.Fatalf("recent holds %d runs, want %d", n, len(names)+1)
+	}
+}